@@ -1,6 +1,12 @@
 package args
 
-import sdk_args "github.com/newrelic/infra-integrations-sdk/v3/args"
+import (
+	"flag"
+	"os"
+	"strings"
+
+	sdk_args "github.com/newrelic/infra-integrations-sdk/v3/args"
+)
 
 type ArgumentList struct {
 	sdk_args.DefaultArgumentList
@@ -9,13 +15,33 @@ type ArgumentList struct {
 	Socket                               string `default:"" help:"Path to the MySQL socket file."`
 	Username                             string `default:"root" help:"Username for database access."`
 	Password                             string `default:"password" help:"Password for the specified user."`
+	PasswordFile                         string `default:"" help:"Path to a file holding the password, such as a mounted Kubernetes secret. Takes precedence over Password."`
+	PasswordCommand                      string `default:"" help:"Command whose standard output is used as the password. Takes precedence over Password."`
+	PasswordVaultURL                     string `default:"" help:"URL of a Vault compatible KV secret holding the password. Takes precedence over Password."`
+	PasswordVaultToken                   string `default:"" help:"Token sent to the Vault endpoint. Defaults to the VAULT_TOKEN environment variable."`
+	PasswordVaultField                   string `default:"password" help:"Field of the Vault secret holding the password."`
+	EnableIAMAuth                        bool   `default:"false" help:"Authenticate to AWS RDS/Aurora with an IAM authentication token generated from the AWS credentials. Requires TLS."`
+	AWSRegion                            string `default:"" help:"AWS region of the RDS/Aurora instance. Defaults to the AWS_REGION environment variable."`
+	AWSProfile                           string `default:"" help:"Profile of the shared AWS credentials file used when the credentials are not set in the environment."`
 	Database                             string `help:"Name of the database."`
+	OptionFile                           string `default:"" help:"Path to a MySQL option file (e.g. ~/.my.cnf). Its user, password, socket and SSL settings are used when not set explicitly."`
+	OptionFileGroup                      string `default:"client" help:"Group of the option file to read the connection settings from."`
+	LoginPath                            string `default:"" help:"Login path stored with mysql_config_editor in ~/.mylogin.cnf. Its settings take precedence over the option file."`
 	ExtraConnectionURLArgs               string `help:"Additional connection parameters in the format attr1=val1&attr2=val2."` // https://github.com/go-sql-driver/mysql#parameters
 	InsecureSkipVerify                   bool   `default:"false" help:"Skip TLS certificate verification when connecting."`
 	EnableTLS                            bool   `default:"false" help:"Use a secure (TLS) connection."`
+	TLSCaFile                            string `default:"" help:"Path to a PEM encoded CA bundle used to verify the server certificate."`
+	TLSCertFile                          string `default:"" help:"Path to a PEM encoded client certificate for mutual TLS."`
+	TLSKeyFile                           string `default:"" help:"Path to the PEM encoded private key of the client certificate."`
+	TLSServerName                        string `default:"" help:"Server name used to verify the server certificate. Defaults to the hostname."`
+	TLSMinVersion                        string `default:"" help:"Minimum TLS version accepted when connecting (1.0, 1.1, 1.2 or 1.3). Defaults to 1.2."`
 	RemoteMonitoring                     bool   `default:"false" help:"Indicates if the monitored entity is remote. Set to true if unsure."`
 	ExtendedMetrics                      bool   `default:"false" help:"Enable collection of extended metrics."`
 	ExtendedInnodbMetrics                bool   `default:"false" help:"Enable collection of extended InnoDB metrics."`
+	InnodbCounterMetrics                 bool   `default:"false" help:"Enable collection of every enabled counter of information_schema.INNODB_METRICS as db.innodb.metrics.* metrics."`
+	InnodbCounterSubsystems              string `default:"[]" help:"A JSON array of INNODB_METRICS subsystems to collect, such as [\"dml\",\"lock\"]. All subsystems are collected when empty."`
+	InnodbStatusMetrics                  bool   `default:"false" help:"Enable parsing of SHOW ENGINE INNODB STATUS into checkpoint age, history list length, pending flushes, semaphore waits and read views metrics. Requires the PROCESS privilege."`
+	DeadlockSamples                      bool   `default:"false" help:"Enable reporting the latest deadlock detected by InnoDB as MysqlDeadlockSample, once per deadlock. Requires the PROCESS privilege."`
 	ExtendedMyIsamMetrics                bool   `default:"false" help:"Enable collection of extended MyISAM metrics."`
 	ExtendedBackupMetrics                bool   `default:"false" help:"Enable collection of active backup operation metrics."`
 	ExtendedBackupHistoryMetrics         bool   `default:"false" help:"Enable collection of historical backup metrics from performance_schema."`
@@ -25,5 +51,58 @@ type ArgumentList struct {
 	SlowQueryMonitoringFetchInterval     int    `default:"30" help:"Fetch interval in seconds for grouped slow queries. Should match the interval in mysql-config.yml."`
 	QueryMonitoringResponseTimeThreshold int    `default:"1" help:"Threshold in milliseconds for query response time to fetch individual query performance metrics."`
 	QueryMonitoringCountThreshold        int    `default:"20" help:"Query count limit for fetching grouped slow and individual query performance metrics."`
+	DatabaseMetrics                      bool   `default:"false" help:"Enable collection of the size of every database, reported as MysqlDatabaseSample of database entities."`
+	DatabaseMetricsMaxTables             int    `default:"10000" help:"Maximum number of tables read from information_schema.TABLES to compute database and table metrics."`
+	TableMetricsLimit                    int    `default:"0" help:"Number of biggest tables reported as MysqlTableSample when DatabaseMetrics is enabled. 0 disables table metrics."`
+	UserMetrics                          bool   `default:"false" help:"Enable collection of per user connection and statement metrics from performance_schema, reported as MysqlUserSample."`
+	UserMetricsByAccount                 bool   `default:"false" help:"Report user metrics per account (user and client host) instead of per user."`
+	UserMetricsLimit                     int    `default:"200" help:"Maximum number of users or accounts reported, the ones running the most statements first."`
+	ClientHostMetrics                    bool   `default:"false" help:"Enable collection of per client host connections and connection errors from performance_schema, reported as MysqlClientHostSample."`
+	ClientHostMetricsLimit               int    `default:"200" help:"Maximum number of client hosts read from performance_schema.hosts and performance_schema.host_cache."`
+	HeartbeatLag                         bool   `default:"false" help:"Enable measuring replication lag from a pt-heartbeat table updated with --utc, reported as cluster.heartbeatLagSeconds."`
+	HeartbeatSchema                      string `default:"percona" help:"Schema of the pt-heartbeat table."`
+	HeartbeatTable                       string `default:"heartbeat" help:"Name of the pt-heartbeat table."`
+	HeartbeatServerID                    int    `default:"0" help:"Server id of the source whose heartbeats are read. The latest heartbeat of any source is used when 0."`
+	ReplicaConnections                   bool   `default:"false" help:"Enable reporting the replicas connected to this source and their binlog dump threads as MysqlReplicaConnectionSample. Requires the REPLICATION SLAVE privilege."`
+	BinlogMetrics                        bool   `default:"false" help:"Enable collection of binary log count, size, position, retention and binlog cache metrics. Requires the REPLICATION CLIENT privilege."`
+	ReplicationApplierMetrics            bool   `default:"false" help:"Enable reporting the state, errors, last transactions and lag of every replication applier worker, coordinator and receiver connection from performance_schema. Requires MySQL 8.0 or later."`
+	CustomMetricsConfig                  string `default:"" help:"Path to a YAML file defining custom queries whose rows are reported as samples of custom event types."`
 	ExcludedPerformanceDatabases         string `default:"[]" help:"A JSON array that lists databases to be excluded from performance metrics collection. System databases are always excluded."`
+	Targets                              string `default:"" help:"Inline JSON array or path to a YAML file listing the MySQL instances to monitor. Each target is reported as its own entity."`
+	MaxConcurrentTargets                 int    `default:"4" help:"Maximum number of targets collected concurrently."`
+	MaxOpenConnections                   int    `default:"2" help:"Maximum number of connections opened to each MySQL instance, shared by all the collectors."`
+	MaxIdleConnections                   int    `default:"2" help:"Maximum number of idle connections kept open to each MySQL instance during a run."`
+	ConnectRetries                       int    `default:"3" help:"Maximum number of retries of transient connection errors, such as lost connections or too many connections. Authentication errors are never retried."`
+	ConnectRetryBackoff                  int    `default:"500" help:"Initial backoff in milliseconds between retries, doubled after every retry."`
+	ConnectRetryDeadline                 int    `default:"30" help:"Total time in seconds after which transient connection errors are no longer retried."`
+	SessionMaxExecutionTime              int    `default:"10000" help:"max_execution_time in milliseconds set on every monitoring session (max_statement_time on MariaDB). 0 keeps the server default."`
+	SessionLockWaitTimeout               int    `default:"5" help:"lock_wait_timeout in seconds set on every monitoring session. 0 keeps the server default."`
+	SessionInnodbLockWaitTimeout         int    `default:"5" help:"innodb_lock_wait_timeout in seconds set on every monitoring session. 0 keeps the server default."`
+	SessionReadOnly                      bool   `default:"true" help:"Set transaction_read_only on every monitoring session."`
+	SessionLongQueryTime                 int    `default:"3600" help:"long_query_time in seconds set on every monitoring session, so that monitoring queries are not written to the slow query log. 0 keeps the server default."`
+}
+
+// ambientEnvironmentVariables are argument environment variables also set by the operating system or the shell,
+// such as USERNAME on Windows, so their presence does not tell that the argument was configured.
+var ambientEnvironmentVariables = map[string]struct{}{
+	"USERNAME": {},
+	"HOSTNAME": {},
+	"PORT":     {},
+}
+
+// IsArgumentSet reports whether the argument with the given flag name, such as `username`, was set through its
+// environment variable or command line flag, so that an explicit value is told apart from its default. Arguments
+// whose environment variable is also set by the operating system are only explicit when set as a flag.
+func IsArgumentSet(name string) bool {
+	envName := strings.ToUpper(name)
+	if _, ambient := ambientEnvironmentVariables[envName]; !ambient && os.Getenv(envName) != "" {
+		return true
+	}
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
 }
@@ -0,0 +1,22 @@
+package args
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsArgumentSet(t *testing.T) {
+	t.Setenv("PASSWORD", "secret")
+	assert.True(t, IsArgumentSet("password"))
+
+	t.Setenv("PASSWORD", "")
+	assert.False(t, IsArgumentSet("password"))
+
+	// Set by the operating system or the shell rather than by the integration configuration
+	for _, name := range []string{"username", "hostname", "port"} {
+		t.Setenv(strings.ToUpper(name), "value")
+		assert.False(t, IsArgumentSet(name), name)
+	}
+}
@@ -0,0 +1,201 @@
+package args
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	errTargetAddressMissing = errors.New("hostname or socket is required")
+	errNoTargets            = errors.New("no targets listed")
+	errDuplicateTarget      = errors.New("targets must have a distinct hostname and port")
+)
+
+// Target describes a single MySQL instance listed in the Targets argument.
+// Fields left empty inherit the value of the top level argument.
+type Target struct {
+	Hostname                     string `yaml:"hostname"`
+	Port                         int    `yaml:"port"`
+	Socket                       string `yaml:"socket"`
+	Username                     string `yaml:"username"`
+	Password                     string `yaml:"password"`
+	PasswordFile                 string `yaml:"password_file"`
+	PasswordCommand              string `yaml:"password_command"`
+	PasswordVaultURL             string `yaml:"password_vault_url"`
+	AWSRegion                    string `yaml:"aws_region"`
+	AWSProfile                   string `yaml:"aws_profile"`
+	Database                     string `yaml:"database"`
+	OptionFile                   string `yaml:"option_file"`
+	OptionFileGroup              string `yaml:"option_file_group"`
+	LoginPath                    string `yaml:"login_path"`
+	ExtraConnectionURLArgs       string `yaml:"extra_connection_url_args"`
+	TLSCaFile                    string `yaml:"tls_ca_file"`
+	TLSCertFile                  string `yaml:"tls_cert_file"`
+	TLSKeyFile                   string `yaml:"tls_key_file"`
+	TLSServerName                string `yaml:"tls_server_name"`
+	EnableTLS                    *bool  `yaml:"enable_tls"`
+	EnableIAMAuth                *bool  `yaml:"enable_iam_auth"`
+	InsecureSkipVerify           *bool  `yaml:"insecure_skip_verify"`
+	OldPasswords                 *bool  `yaml:"old_passwords"`
+	ExtendedMetrics              *bool  `yaml:"extended_metrics"`
+	ExtendedInnodbMetrics        *bool  `yaml:"extended_innodb_metrics"`
+	ExtendedMyIsamMetrics        *bool  `yaml:"extended_my_isam_metrics"`
+	ExtendedBackupMetrics        *bool  `yaml:"extended_backup_metrics"`
+	ExtendedBackupHistoryMetrics *bool  `yaml:"extended_backup_history_metrics"`
+	EnableQueryMonitoring        *bool  `yaml:"enable_query_monitoring"`
+}
+
+// targetsFile is the layout of a YAML targets file using a top level `targets` key.
+type targetsFile struct {
+	Targets []Target `yaml:"targets"`
+}
+
+// GetTargets returns one ArgumentList per MySQL instance to be monitored.
+// When no targets are configured the top level arguments are the only target.
+func (args ArgumentList) GetTargets() ([]ArgumentList, error) {
+	if strings.TrimSpace(args.Targets) == "" {
+		return []ArgumentList{args}, nil
+	}
+
+	targets, err := ParseTargets(args.Targets)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("%w in %s", errNoTargets, args.Targets)
+	}
+
+	// The entity, the state and the rates of each target are keyed by its hostname and port, socket targets
+	// included, so targets sharing them would overwrite each other.
+	targetArgs := make([]ArgumentList, 0, len(targets))
+	seen := make(map[string]int, len(targets))
+	for idx, target := range targets {
+		if target.Hostname == "" && target.Socket == "" {
+			return nil, fmt.Errorf("target %d: %w", idx, errTargetAddressMissing)
+		}
+		targetArg := target.Apply(args)
+		address := fmt.Sprint(targetArg.Hostname, ":", targetArg.Port)
+		if first, ok := seen[address]; ok {
+			return nil, fmt.Errorf("targets %d and %d are both %s: %w", first, idx, address, errDuplicateTarget)
+		}
+		seen[address] = idx
+		targetArgs = append(targetArgs, targetArg)
+	}
+	return targetArgs, nil
+}
+
+// ParseTargets parses an inline JSON array of targets or, otherwise, reads the targets from a YAML file.
+// The file may contain either a top level list or a `targets` key holding the list.
+func ParseTargets(value string) ([]Target, error) {
+	value = strings.TrimSpace(value)
+
+	data := []byte(value)
+	if !strings.HasPrefix(value, "[") {
+		fileData, err := os.ReadFile(value)
+		if err != nil {
+			return nil, fmt.Errorf("error reading targets file %s: %w", value, err)
+		}
+		data = fileData
+	}
+
+	// YAML is a superset of JSON, so the same decoder handles both inline and file targets.
+	var targets []Target
+	if err := yaml.Unmarshal(data, &targets); err == nil {
+		return targets, nil
+	}
+
+	var file targetsFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing targets: %w", err)
+	}
+	return file.Targets, nil
+}
+
+// Apply returns a copy of base overridden with the values defined in the target.
+// Targets are always reported as remote entities so that each one gets its own entity.
+func (t Target) Apply(base ArgumentList) ArgumentList {
+	args := base
+	args.Targets = ""
+	args.RemoteMonitoring = true
+
+	if t.Hostname != "" {
+		args.Hostname = t.Hostname
+	}
+	if t.Port != 0 {
+		args.Port = t.Port
+	}
+	// A target is either reached over TCP or through a socket, never inheriting the other one.
+	args.Socket = t.Socket
+	if t.Username != "" {
+		args.Username = t.Username
+	}
+	if t.Password != "" {
+		args.Password = t.Password
+	}
+	if t.PasswordFile != "" {
+		args.PasswordFile = t.PasswordFile
+	}
+	if t.PasswordCommand != "" {
+		args.PasswordCommand = t.PasswordCommand
+	}
+	if t.PasswordVaultURL != "" {
+		args.PasswordVaultURL = t.PasswordVaultURL
+	}
+	if t.AWSRegion != "" {
+		args.AWSRegion = t.AWSRegion
+	}
+	if t.AWSProfile != "" {
+		args.AWSProfile = t.AWSProfile
+	}
+	if t.Database != "" {
+		args.Database = t.Database
+	}
+	if t.OptionFile != "" {
+		args.OptionFile = t.OptionFile
+	}
+	if t.OptionFileGroup != "" {
+		args.OptionFileGroup = t.OptionFileGroup
+	}
+	if t.LoginPath != "" {
+		args.LoginPath = t.LoginPath
+	}
+	if t.ExtraConnectionURLArgs != "" {
+		args.ExtraConnectionURLArgs = t.ExtraConnectionURLArgs
+	}
+	if t.TLSCaFile != "" {
+		args.TLSCaFile = t.TLSCaFile
+	}
+	if t.TLSCertFile != "" {
+		args.TLSCertFile = t.TLSCertFile
+	}
+	if t.TLSKeyFile != "" {
+		args.TLSKeyFile = t.TLSKeyFile
+	}
+	if t.TLSServerName != "" {
+		args.TLSServerName = t.TLSServerName
+	}
+
+	applyBool(&args.EnableTLS, t.EnableTLS)
+	applyBool(&args.EnableIAMAuth, t.EnableIAMAuth)
+	applyBool(&args.InsecureSkipVerify, t.InsecureSkipVerify)
+	applyBool(&args.OldPasswords, t.OldPasswords)
+	applyBool(&args.ExtendedMetrics, t.ExtendedMetrics)
+	applyBool(&args.ExtendedInnodbMetrics, t.ExtendedInnodbMetrics)
+	applyBool(&args.ExtendedMyIsamMetrics, t.ExtendedMyIsamMetrics)
+	applyBool(&args.ExtendedBackupMetrics, t.ExtendedBackupMetrics)
+	applyBool(&args.ExtendedBackupHistoryMetrics, t.ExtendedBackupHistoryMetrics)
+	applyBool(&args.EnableQueryMonitoring, t.EnableQueryMonitoring)
+
+	return args
+}
+
+func applyBool(dst *bool, value *bool) {
+	if value != nil {
+		*dst = *value
+	}
+}
@@ -0,0 +1,141 @@
+package args
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTargetsInlineJSON(t *testing.T) {
+	targets, err := ParseTargets(`[{"hostname":"db1","port":3307,"extended_metrics":true},{"socket":"/tmp/mysql.sock"}]`)
+	require.NoError(t, err)
+	require.Len(t, targets, 2)
+
+	assert.Equal(t, "db1", targets[0].Hostname)
+	assert.Equal(t, 3307, targets[0].Port)
+	require.NotNil(t, targets[0].ExtendedMetrics)
+	assert.True(t, *targets[0].ExtendedMetrics)
+	assert.Equal(t, "/tmp/mysql.sock", targets[1].Socket)
+}
+
+func TestParseTargetsYAMLFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{
+			name: "TargetsKey",
+			content: `targets:
+  - hostname: db1
+    username: monitor
+  - hostname: db2
+    enable_query_monitoring: true
+`,
+		},
+		{
+			name: "TopLevelList",
+			content: `- hostname: db1
+  username: monitor
+- hostname: db2
+  enable_query_monitoring: true
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "targets.yml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			targets, err := ParseTargets(path)
+			require.NoError(t, err)
+			require.Len(t, targets, 2)
+			assert.Equal(t, "monitor", targets[0].Username)
+			assert.Equal(t, "db2", targets[1].Hostname)
+			require.NotNil(t, targets[1].EnableQueryMonitoring)
+			assert.True(t, *targets[1].EnableQueryMonitoring)
+		})
+	}
+}
+
+func TestParseTargetsMissingFile(t *testing.T) {
+	_, err := ParseTargets(filepath.Join(t.TempDir(), "missing.yml"))
+	assert.Error(t, err)
+}
+
+func TestTargetApplyInheritsUnsetValues(t *testing.T) {
+	base := ArgumentList{
+		Hostname:        "localhost",
+		Port:            3306,
+		Socket:          "/var/run/mysqld.sock",
+		Username:        "root",
+		Password:        "secret",
+		ExtendedMetrics: true,
+		Targets:         "[]",
+	}
+	disabled := false
+	target := Target{Hostname: "db1", Username: "monitor", ExtendedMetrics: &disabled}
+
+	args := target.Apply(base)
+
+	assert.Equal(t, "db1", args.Hostname)
+	assert.Equal(t, 3306, args.Port)
+	assert.Equal(t, "", args.Socket)
+	assert.Equal(t, "monitor", args.Username)
+	assert.Equal(t, "secret", args.Password)
+	assert.False(t, args.ExtendedMetrics)
+	assert.True(t, args.RemoteMonitoring)
+	assert.Equal(t, "", args.Targets)
+}
+
+func TestGetTargets(t *testing.T) {
+	t.Run("NoTargets", func(t *testing.T) {
+		base := ArgumentList{Hostname: "localhost", Port: 3306}
+		targets, err := base.GetTargets()
+		require.NoError(t, err)
+		assert.Equal(t, []ArgumentList{base}, targets)
+	})
+
+	t.Run("MultipleTargets", func(t *testing.T) {
+		base := ArgumentList{Hostname: "localhost", Port: 3306, Targets: `[{"hostname":"db1"},{"hostname":"db2","port":3307}]`}
+		targets, err := base.GetTargets()
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+		assert.Equal(t, "db1", targets[0].Hostname)
+		assert.Equal(t, 3306, targets[0].Port)
+		assert.Equal(t, "db2", targets[1].Hostname)
+		assert.Equal(t, 3307, targets[1].Port)
+	})
+
+	t.Run("TargetWithoutAddress", func(t *testing.T) {
+		base := ArgumentList{Targets: `[{"username":"monitor"}]`}
+		_, err := base.GetTargets()
+		assert.ErrorIs(t, err, errTargetAddressMissing)
+	})
+
+	t.Run("EmptyTargets", func(t *testing.T) {
+		base := ArgumentList{Targets: `[]`}
+		_, err := base.GetTargets()
+		assert.ErrorIs(t, err, errNoTargets)
+	})
+
+	t.Run("SocketTargetsSharingAddress", func(t *testing.T) {
+		base := ArgumentList{Hostname: "localhost", Port: 3306,
+			Targets: `[{"socket":"/var/run/mysqld/a.sock"},{"socket":"/var/run/mysqld/b.sock"}]`}
+		_, err := base.GetTargets()
+		assert.ErrorIs(t, err, errDuplicateTarget)
+	})
+
+	t.Run("SocketTargetsWithDistinctPorts", func(t *testing.T) {
+		base := ArgumentList{Hostname: "localhost", Port: 3306,
+			Targets: `[{"socket":"/var/run/mysqld/a.sock","port":3306},{"socket":"/var/run/mysqld/b.sock","port":3307}]`}
+		targets, err := base.GetTargets()
+		require.NoError(t, err)
+		require.Len(t, targets, 2)
+		assert.Equal(t, "/var/run/mysqld/b.sock", targets[1].Socket)
+		assert.Equal(t, 3307, targets[1].Port)
+	})
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	dbutils "github.com/newrelic/nri-mysql/src/dbutils"
+)
+
+const availabilitySampleName = "MysqlAvailabilitySample"
+
+// now returns the current time, replaced in tests.
+var now = time.Now
+
+// availability holds the result of checking whether a MySQL server can be reached.
+type availability struct {
+	connected      bool
+	connectLatency time.Duration
+	pingLatency    time.Duration
+	retries        int
+	err            error
+}
+
+// checkAvailability establishes the first connection to the server and pings it once more to measure the round trip.
+func checkAvailability(db dataSource) availability {
+	var status availability
+
+	start := now()
+	if err := db.ping(); err != nil {
+		status.err = err
+		return status
+	}
+	status.connected = true
+	status.connectLatency = now().Sub(start)
+
+	start = now()
+	if err := db.ping(); err != nil {
+		status.err = err
+		return status
+	}
+	status.pingLatency = now().Sub(start)
+
+	return status
+}
+
+// populateAvailability reports whether the collection succeeded along with the connection latencies.
+// err is the error that stopped the collection, if any, and is reported by its class only.
+func populateAvailability(ms *metric.Set, status availability, err error) {
+	up := 1
+	if err != nil {
+		up = 0
+	}
+
+	availabilityMetrics := map[string]interface{}{
+		"db.up":             up,
+		"db.connectRetries": status.retries,
+	}
+	if status.connected {
+		availabilityMetrics["db.connectLatencyMs"] = float64(status.connectLatency.Microseconds()) / 1000
+	}
+	if status.connected && status.err == nil {
+		availabilityMetrics["db.pingLatencyMs"] = float64(status.pingLatency.Microseconds()) / 1000
+	}
+
+	for name, value := range availabilityMetrics {
+		if setErr := ms.SetMetric(name, value, metric.GAUGE); setErr != nil {
+			log.Warn("Error setting value: %s", setErr)
+		}
+	}
+
+	if err != nil {
+		if setErr := ms.SetMetric("db.errorClass", dbutils.ClassifyError(err), metric.ATTRIBUTE); setErr != nil {
+			log.Warn("Error setting value: %s", setErr)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/stretchr/testify/assert"
+)
+
+var errConnectionRefused = errors.New("connection refused")
+
+type unreachableDB struct {
+	testdb
+	err error
+}
+
+func (d unreachableDB) ping() error {
+	return d.err
+}
+
+// steppingClock returns a clock advancing by step every time it is read.
+func steppingClock(step time.Duration) func() time.Time {
+	current := time.Date(2026, 9, 14, 10, 0, 0, 0, time.UTC)
+	return func() time.Time {
+		current = current.Add(step)
+		return current
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	now = steppingClock(5 * time.Millisecond)
+	defer func() { now = time.Now }()
+
+	status := checkAvailability(testdb{})
+	assert.NoError(t, status.err)
+	assert.True(t, status.connected)
+	assert.Equal(t, 5*time.Millisecond, status.connectLatency)
+	assert.Equal(t, 5*time.Millisecond, status.pingLatency)
+}
+
+func TestCheckAvailabilityUnreachable(t *testing.T) {
+	status := checkAvailability(unreachableDB{err: errConnectionRefused})
+	assert.ErrorIs(t, status.err, errConnectionRefused)
+	assert.False(t, status.connected)
+	assert.Zero(t, status.connectLatency)
+	assert.Zero(t, status.pingLatency)
+}
+
+func TestPopulateAvailabilityUp(t *testing.T) {
+	ms := metric.NewSet(availabilitySampleName, nil)
+	// Fast servers measured with a coarse clock have no latency, which is still reported
+	populateAvailability(ms, availability{connected: true}, nil)
+
+	assert.Equal(t, 1., ms.Metrics["db.up"])
+	assert.Equal(t, 0., ms.Metrics["db.connectLatencyMs"])
+	assert.Equal(t, 0., ms.Metrics["db.pingLatencyMs"])
+	assert.NotContains(t, ms.Metrics, "db.errorClass")
+}
+
+func TestPopulateAvailabilityDown(t *testing.T) {
+	err := &mysql.MySQLError{Number: 1045, Message: "Access denied"}
+	ms := metric.NewSet(availabilitySampleName, nil)
+	populateAvailability(ms, availability{retries: 2, err: err}, err)
+
+	assert.Equal(t, 0., ms.Metrics["db.up"])
+	assert.Equal(t, 2., ms.Metrics["db.connectRetries"])
+	assert.Equal(t, "auth", ms.Metrics["db.errorClass"])
+	assert.NotContains(t, ms.Metrics, "db.connectLatencyMs")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+)
+
+const (
+	binaryLogsQuery                      = "SHOW BINARY LOGS"
+	binlogStatusQueryBelowVersion8Point4 = "SHOW MASTER STATUS"
+	// From MySQL 8.4 SHOW MASTER STATUS is removed and SHOW BINARY LOG STATUS should be used instead
+	binlogStatusQueryForVersion8Point4AndAbove = "SHOW BINARY LOG STATUS"
+)
+
+var binlogMetrics = map[string][]interface{}{
+	"db.binlog.files":                 {"binlog_files", metric.GAUGE},
+	"db.binlog.totalBytes":            {"binlog_total_bytes", metric.GAUGE},
+	"db.binlog.currentFile":           {"binlog_current_file", metric.ATTRIBUTE},
+	"db.binlog.currentPosition":       {"binlog_current_position", metric.GAUGE},
+	"db.binlog.expireLogsSeconds":     {"binlog_expire_logs_seconds", metric.GAUGE},
+	"db.binlog.cacheUsePerSecond":     {"Binlog_cache_use", metric.PRATE},
+	"db.binlog.cacheDiskUsePerSecond": {"Binlog_cache_disk_use", metric.PRATE},
+	"db.binlog.cacheDiskUseRatio":     {binlogCacheDiskUseRatio, metric.GAUGE},
+	"db.binlog.stmtCacheDiskUseRatio": {binlogStmtCacheDiskUseRatio, metric.GAUGE},
+}
+
+// getBinlogStatusQuery returns the query reporting the current binary log position. MariaDB supports SHOW MASTER
+// STATUS in every version.
+func getBinlogStatusQuery(dbVersion string, version string) string {
+	if isMariaDBServer(version) || isDBVersionLessThan8Point4(dbVersion) {
+		return binlogStatusQueryBelowVersion8Point4
+	}
+	return binlogStatusQueryForVersion8Point4AndAbove
+}
+
+/*
+populateBinlogMetrics reports the number and size of the binary logs, the current binlog position, the binlog cache
+usage and the retention of the binary logs. Servers with log_bin disabled are skipped, and the binary logs
+metrics are left out when the user lacks the REPLICATION CLIENT privilege.
+*/
+func populateBinlogMetrics(ms *metric.Set, db dataSource, rawInventory map[string]interface{}, rawMetrics map[string]interface{},
+	dbVersion string) {
+	if !strings.EqualFold(fmt.Sprint(rawInventory["log_bin"]), "ON") && rawInventory["log_bin"] != 1 {
+		log.Debug("Binary logging is disabled, skipping binlog metrics")
+		return
+	}
+
+	raw := map[string]interface{}{}
+	for _, key := range []string{"Binlog_cache_use", "Binlog_cache_disk_use", "Binlog_stmt_cache_use", "Binlog_stmt_cache_disk_use"} {
+		if value, ok := rawMetrics[key]; ok {
+			raw[key] = value
+		}
+	}
+	if seconds, ok := binlogExpireLogsSeconds(rawInventory); ok {
+		raw["binlog_expire_logs_seconds"] = seconds
+	}
+
+	if logs, err := db.queryRows(binaryLogsQuery, ""); err != nil {
+		log.Warn("Can't get binary logs, not enough privileges (must grant REPLICATION CLIENT): %v", err)
+	} else {
+		total := 0
+		for _, binlog := range logs {
+			if size, ok := binlog["File_size"].(int); ok {
+				total += size
+			}
+		}
+		raw["binlog_files"] = len(logs)
+		raw["binlog_total_bytes"] = total
+	}
+
+	if status, err := db.queryRows(getBinlogStatusQuery(dbVersion, fmt.Sprint(rawInventory["version"])), ""); err != nil {
+		log.Warn("Can't get current binary log position: %v", err)
+	} else if len(status) > 0 {
+		raw["binlog_current_file"] = fmt.Sprint(status[0]["File"])
+		raw["binlog_current_position"] = status[0]["Position"]
+	}
+
+	populatePartialMetrics(ms, raw, availableMetrics(binlogMetrics, raw), dbVersion)
+}
+
+// binlogExpireLogsSeconds returns the retention of the binary logs from binlog_expire_logs_seconds or, on servers
+// without it or when it is unset, from expire_logs_days.
+func binlogExpireLogsSeconds(rawInventory map[string]interface{}) (int, bool) {
+	if seconds, ok := rawInventory["binlog_expire_logs_seconds"].(int); ok && seconds > 0 {
+		return seconds, true
+	}
+	switch days := rawInventory["expire_logs_days"].(type) {
+	case int:
+		return days * int(24*time.Hour/time.Second), true
+	case float64:
+		return int(days * float64(24*time.Hour/time.Second)), true
+	}
+	if seconds, ok := rawInventory["binlog_expire_logs_seconds"].(int); ok {
+		return seconds, true
+	}
+	return 0, false
+}
+
+func binlogCacheDiskUseRatio(metrics map[string]interface{}) (float64, bool) {
+	return cacheDiskUseRatio(metrics, "Binlog_cache_disk_use", "Binlog_cache_use")
+}
+
+func binlogStmtCacheDiskUseRatio(metrics map[string]interface{}) (float64, bool) {
+	return cacheDiskUseRatio(metrics, "Binlog_stmt_cache_disk_use", "Binlog_stmt_cache_use")
+}
+
+// cacheDiskUseRatio returns the share of the transactions using the binlog cache that exceeded it and used a
+// temporary file.
+func cacheDiskUseRatio(metrics map[string]interface{}, diskUse string, use string) (float64, bool) {
+	diskUseValue, ok1 := metrics[diskUse].(int)
+	useValue, ok2 := metrics[use].(int)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	if useValue == 0 {
+		return 0, true
+	}
+	return float64(diskUseValue) / float64(useValue), true
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	"github.com/stretchr/testify/assert"
+)
+
+// binlogDB returns the binary logs and the current binlog position.
+type binlogDB struct {
+	testdb
+	logs    []map[string]interface{}
+	status  []map[string]interface{}
+	queries []string
+}
+
+func (d *binlogDB) queryRows(query string, _ string, _ ...interface{}) ([]map[string]interface{}, error) {
+	d.queries = append(d.queries, query)
+	if query == binaryLogsQuery {
+		return d.logs, nil
+	}
+	return d.status, nil
+}
+
+func TestPopulateBinlogMetrics(t *testing.T) {
+	db := &binlogDB{
+		logs: []map[string]interface{}{
+			{"Log_name": "binlog.000007", "File_size": 1000, "Encrypted": "No"},
+			{"Log_name": "binlog.000008", "File_size": 500, "Encrypted": "No"},
+		},
+		status: []map[string]interface{}{{"File": "binlog.000008", "Position": 500}},
+	}
+	inventory := map[string]interface{}{"log_bin": "ON", "binlog_expire_logs_seconds": 2592000, "expire_logs_days": 0}
+	rawMetrics := map[string]interface{}{"Binlog_cache_use": 200, "Binlog_cache_disk_use": 10,
+		"Binlog_stmt_cache_use": 0, "Binlog_stmt_cache_disk_use": 0}
+
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateBinlogMetrics(ms, db, inventory, rawMetrics, "8.4.2")
+
+	assert.Equal(t, []string{binaryLogsQuery, binlogStatusQueryForVersion8Point4AndAbove}, db.queries)
+	assert.Equal(t, 2., ms.Metrics["db.binlog.files"])
+	assert.Equal(t, 1500., ms.Metrics["db.binlog.totalBytes"])
+	assert.Equal(t, "binlog.000008", ms.Metrics["db.binlog.currentFile"])
+	assert.Equal(t, 500., ms.Metrics["db.binlog.currentPosition"])
+	assert.Equal(t, 2592000., ms.Metrics["db.binlog.expireLogsSeconds"])
+	assert.Equal(t, 0.05, ms.Metrics["db.binlog.cacheDiskUseRatio"])
+	assert.Equal(t, 0., ms.Metrics["db.binlog.stmtCacheDiskUseRatio"])
+	// The creation time of the binary logs is not available, so their age is not reported
+	assert.NotContains(t, ms.Metrics, "db.binlog.oldestFileFirstSeenSeconds")
+}
+
+func TestPopulateBinlogMetricsMariaDB(t *testing.T) {
+	db := &binlogDB{status: []map[string]interface{}{{"File": "mariadb-bin.000003", "Position": 342}}}
+	inventory := map[string]interface{}{"log_bin": "ON", "version": "11.4.2-MariaDB", "expire_logs_days": 10}
+
+	ms := metric.NewSet("MysqlSample", nil)
+	populateBinlogMetrics(ms, db, inventory, map[string]interface{}{}, "11.4.2")
+
+	assert.Equal(t, []string{binaryLogsQuery, binlogStatusQueryBelowVersion8Point4}, db.queries)
+	assert.Equal(t, "mariadb-bin.000003", ms.Metrics["db.binlog.currentFile"])
+	assert.Equal(t, 864000., ms.Metrics["db.binlog.expireLogsSeconds"])
+}
+
+func TestPopulateBinlogMetricsDisabled(t *testing.T) {
+	db := &binlogDB{}
+	ms := metric.NewSet("MysqlSample", nil)
+	populateBinlogMetrics(ms, db, map[string]interface{}{"log_bin": "OFF"}, map[string]interface{}{}, "8.0.36")
+
+	assert.Empty(t, db.queries)
+	assert.NotContains(t, ms.Metrics, "db.binlog.files")
+}
+
+func TestBinlogExpireLogsSeconds(t *testing.T) {
+	seconds, ok := binlogExpireLogsSeconds(map[string]interface{}{"expire_logs_days": 7})
+	assert.True(t, ok)
+	assert.Equal(t, 604800, seconds)
+
+	_, ok = binlogExpireLogsSeconds(map[string]interface{}{})
+	assert.False(t, ok)
+}
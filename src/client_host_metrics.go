@@ -0,0 +1,109 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+)
+
+const clientHostSampleName = "MysqlClientHostSample"
+
+// clientHostConnectionsQuery returns the connections of every client host, skipping background threads.
+const clientHostConnectionsQuery = `SELECT HOST AS client_host, CURRENT_CONNECTIONS AS current_connections,
+	TOTAL_CONNECTIONS AS total_connections
+	FROM performance_schema.hosts
+	WHERE HOST IS NOT NULL
+	ORDER BY CURRENT_CONNECTIONS DESC
+	LIMIT ?`
+
+/*
+clientHostErrorsQuery returns the connection errors of every client host from the host cache. Hosts failing to
+connect are only found here, as they never get a thread. The host cache is only populated when skip_name_resolve
+is disabled.
+*/
+const clientHostErrorsQuery = `SELECT COALESCE(HOST, IP) AS client_host, IP AS client_ip,
+	SUM_CONNECT_ERRORS AS connect_errors, COUNT_HOST_BLOCKED_ERRORS AS host_blocked_errors,
+	COUNT_HANDSHAKE_ERRORS AS handshake_errors, COUNT_AUTHENTICATION_ERRORS AS authentication_errors,
+	COUNT_AUTH_PLUGIN_ERRORS AS auth_plugin_errors, COUNT_HOST_ACL_ERRORS AS host_acl_errors,
+	COUNT_MAX_USER_CONNECTIONS_ERRORS AS max_user_connections_errors,
+	COUNT_MAX_USER_CONNECTIONS_PER_HOUR_ERRORS AS max_user_connections_per_hour_errors,
+	COUNT_SSL_ERRORS AS ssl_errors, COUNT_DEFAULT_DATABASE_ERRORS AS default_database_errors,
+	COUNT_INIT_CONNECT_ERRORS AS init_connect_errors, COUNT_LOCAL_ERRORS AS local_errors,
+	COUNT_UNKNOWN_ERRORS AS unknown_errors
+	FROM performance_schema.host_cache
+	ORDER BY SUM_CONNECT_ERRORS DESC
+	LIMIT ?`
+
+// clientHostMetrics counters are reported per interval with PDELTA, like the user metrics.
+var clientHostMetrics = map[string][]interface{}{
+	"clientHost.currentConnections":              {"current_connections", metric.GAUGE},
+	"clientHost.connections":                     {"total_connections", metric.PDELTA},
+	"clientHost.consecutiveConnectErrors":        {"connect_errors", metric.GAUGE},
+	"clientHost.hostBlockedErrors":               {"host_blocked_errors", metric.PDELTA},
+	"clientHost.handshakeErrors":                 {"handshake_errors", metric.PDELTA},
+	"clientHost.authenticationErrors":            {"authentication_errors", metric.PDELTA},
+	"clientHost.authPluginErrors":                {"auth_plugin_errors", metric.PDELTA},
+	"clientHost.hostAclErrors":                   {"host_acl_errors", metric.PDELTA},
+	"clientHost.maxUserConnectionsErrors":        {"max_user_connections_errors", metric.PDELTA},
+	"clientHost.maxUserConnectionsPerHourErrors": {"max_user_connections_per_hour_errors", metric.PDELTA},
+	"clientHost.sslErrors":                       {"ssl_errors", metric.PDELTA},
+	"clientHost.defaultDatabaseErrors":           {"default_database_errors", metric.PDELTA},
+	"clientHost.initConnectErrors":               {"init_connect_errors", metric.PDELTA},
+	"clientHost.localErrors":                     {"local_errors", metric.PDELTA},
+	"clientHost.unknownErrors":                   {"unknown_errors", metric.PDELTA},
+}
+
+// populateClientHostMetrics reports the connections and connection errors of every client host as
+// MysqlClientHostSample of the node entity, merging performance_schema.hosts and performance_schema.host_cache.
+func populateClientHostMetrics(e *integration.Entity, db dataSource, args arguments.ArgumentList) {
+	hosts := map[string]map[string]interface{}{}
+
+	connections, err := db.queryRows(clientHostConnectionsQuery, "", args.ClientHostMetricsLimit)
+	if err != nil {
+		log.Warn("Can't get client host connections (performance_schema may not be enabled): %v", err)
+	}
+	errorRows, err := db.queryRows(clientHostErrorsQuery, "", args.ClientHostMetricsLimit)
+	if err != nil {
+		log.Warn("Can't get client host connection errors: %v", err)
+	}
+
+	for _, row := range append(connections, errorRows...) {
+		host := fmt.Sprint(row["client_host"])
+		if hosts[host] == nil {
+			hosts[host] = map[string]interface{}{}
+		}
+		for key, value := range row {
+			hosts[host][key] = value
+		}
+	}
+
+	names := make([]string, 0, len(hosts))
+	for host := range hosts {
+		names = append(names, host)
+	}
+	sort.Strings(names)
+
+	for _, host := range names {
+		row := hosts[host]
+		attributes := []attribute.Attribute{attribute.Attr("clientHost", host)}
+		if ip, ok := row["client_ip"]; ok && fmt.Sprint(ip) != host {
+			attributes = append(attributes, attribute.Attr("clientIp", fmt.Sprint(ip)))
+		}
+
+		ms := infrautils.MetricSet(
+			e,
+			clientHostSampleName,
+			args.Hostname,
+			args.Port,
+			args.RemoteMonitoring,
+			attributes...,
+		)
+		populatePartialMetrics(ms, row, availableMetrics(clientHostMetrics, row), "")
+	}
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// clientHostDB returns the rows of the client host connections and host cache queries.
+type clientHostDB struct {
+	testdb
+	connections []map[string]interface{}
+	hostCache   []map[string]interface{}
+}
+
+func (d clientHostDB) queryRows(query string, _ string, _ ...interface{}) ([]map[string]interface{}, error) {
+	if query == clientHostErrorsQuery {
+		return d.hostCache, nil
+	}
+	return d.connections, nil
+}
+
+func TestPopulateClientHostMetricsMergesHosts(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	db := clientHostDB{
+		connections: []map[string]interface{}{
+			{"client_host": "app-1", "current_connections": 5, "total_connections": 40},
+		},
+		hostCache: []map[string]interface{}{
+			{"client_host": "app-1", "client_ip": "10.0.0.1", "connect_errors": 0, "authentication_errors": 2},
+			{"client_host": "10.0.0.9", "client_ip": "10.0.0.9", "connect_errors": 100, "host_blocked_errors": 7},
+		},
+	}
+	populateClientHostMetrics(e, db, arguments.ArgumentList{Port: 3306, ClientHostMetricsLimit: 10})
+
+	require.Len(t, e.Metrics, 2)
+
+	blocked := e.Metrics[0].Metrics
+	assert.Equal(t, clientHostSampleName, blocked["event_type"])
+	assert.Equal(t, "10.0.0.9", blocked["clientHost"])
+	assert.NotContains(t, blocked, "clientIp")
+	assert.Equal(t, 100., blocked["clientHost.consecutiveConnectErrors"])
+	assert.NotContains(t, blocked, "clientHost.currentConnections")
+
+	app := e.Metrics[1].Metrics
+	assert.Equal(t, "app-1", app["clientHost"])
+	assert.Equal(t, "10.0.0.1", app["clientIp"])
+	assert.Equal(t, 5., app["clientHost.currentConnections"])
+	assert.Contains(t, app, "clientHost.authenticationErrors")
+}
+
+func TestPopulateClientHostMetricsHostsMissingFromOneTable(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	db := clientHostDB{
+		// app-2 has no host cache entry, as with skip_name_resolve, and 10.0.0.7 never got a thread
+		connections: []map[string]interface{}{
+			{"client_host": "app-2", "current_connections": 3, "total_connections": 12},
+		},
+		hostCache: []map[string]interface{}{
+			{"client_host": "db-admin", "client_ip": "10.0.0.7", "connect_errors": 4, "handshake_errors": 4},
+		},
+	}
+	populateClientHostMetrics(e, db, arguments.ArgumentList{Port: 3306, ClientHostMetricsLimit: 10})
+
+	require.Len(t, e.Metrics, 2)
+
+	connected := e.Metrics[0].Metrics
+	assert.Equal(t, "app-2", connected["clientHost"])
+	assert.NotContains(t, connected, "clientIp")
+	assert.Equal(t, 3., connected["clientHost.currentConnections"])
+	assert.NotContains(t, connected, "clientHost.consecutiveConnectErrors")
+	assert.NotContains(t, connected, "clientHost.handshakeErrors")
+
+	failing := e.Metrics[1].Metrics
+	assert.Equal(t, "db-admin", failing["clientHost"])
+	assert.Equal(t, "10.0.0.7", failing["clientIp"])
+	assert.Equal(t, 4., failing["clientHost.consecutiveConnectErrors"])
+	assert.Contains(t, failing, "clientHost.handshakeErrors")
+	assert.NotContains(t, failing, "clientHost.currentConnections")
+	assert.NotContains(t, failing, "clientHost.connections")
+}
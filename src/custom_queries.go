@@ -0,0 +1,169 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+	"go.yaml.in/yaml/v3"
+)
+
+var (
+	errCustomQueryMissing     = errors.New("query is required")
+	errCustomEventTypeMissing = errors.New("event_type is required")
+	errInvalidMetricType      = errors.New("invalid metric type")
+)
+
+// customMetricTypes maps the metric types accepted in the custom queries file to the SDK source types.
+var customMetricTypes = map[string]metric.SourceType{
+	"gauge":     metric.GAUGE,
+	"rate":      metric.RATE,
+	"delta":     metric.DELTA,
+	"attribute": metric.ATTRIBUTE,
+}
+
+// customQuery is a user defined query whose rows are reported as samples of EventType.
+type customQuery struct {
+	Query     string `yaml:"query"`
+	EventType string `yaml:"event_type"`
+	// MetricTypes sets the type of each column: gauge, rate, delta or attribute. Columns not listed are reported
+	// as gauges when numeric and as attributes otherwise.
+	MetricTypes map[string]string `yaml:"metric_types"`
+	// Database is the default database the query runs against, for queries using unqualified table names.
+	Database string `yaml:"database"`
+	// IntervalMultiplier runs the query once every IntervalMultiplier runs of the integration.
+	IntervalMultiplier int `yaml:"interval_multiplier"`
+}
+
+type customQueriesFile struct {
+	Queries []customQuery `yaml:"queries"`
+}
+
+// loadCustomQueries reads and validates the custom queries file.
+func loadCustomQueries(path string) ([]customQuery, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading custom queries file %s: %w", path, err)
+	}
+
+	var file customQueriesFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("error parsing custom queries file %s: %w", path, err)
+	}
+
+	for idx, query := range file.Queries {
+		if err := query.validate(); err != nil {
+			return nil, fmt.Errorf("custom query %d: %w", idx, err)
+		}
+	}
+	return file.Queries, nil
+}
+
+func (q customQuery) validate() error {
+	if strings.TrimSpace(q.Query) == "" {
+		return errCustomQueryMissing
+	}
+	if strings.TrimSpace(q.EventType) == "" {
+		return errCustomEventTypeMissing
+	}
+	for column, metricType := range q.MetricTypes {
+		if _, ok := customMetricTypes[strings.ToLower(metricType)]; !ok {
+			return fmt.Errorf("%w %q for column %s", errInvalidMetricType, metricType, column)
+		}
+	}
+	return nil
+}
+
+// id identifies the query in the state store.
+func (q customQuery) id() string {
+	sum := sha256.Sum256([]byte(q.Database + "\n" + q.Query))
+	return q.EventType + "/" + hex.EncodeToString(sum[:8])
+}
+
+// isDue reports whether the query runs in this execution according to its interval multiplier,
+// keeping the count of skipped runs in the state store.
+func (q customQuery) isDue(state persist.Storer, args arguments.ArgumentList) bool {
+	if q.IntervalMultiplier <= 1 {
+		return true
+	}
+
+	key := stateKey(args, "customQuery", q.id())
+	var skipped int
+	if _, err := state.Get(key, &skipped); err != nil || skipped+1 >= q.IntervalMultiplier {
+		state.Set(key, 0)
+		return true
+	}
+	state.Set(key, skipped+1)
+	return false
+}
+
+// metricsDefinition returns the definition of the metrics of a row, in the format used by populatePartialMetrics,
+// along with the attributes identifying the row.
+func (q customQuery) metricsDefinition(row map[string]interface{}) (map[string][]interface{}, []attribute.Attribute) {
+	definition := map[string][]interface{}{}
+	var attributes []attribute.Attribute
+
+	for column, value := range row {
+		sourceType, ok := customMetricTypes[strings.ToLower(q.MetricTypes[column])]
+		if !ok {
+			sourceType = metric.ATTRIBUTE
+			switch value.(type) {
+			case int, float64:
+				sourceType = metric.GAUGE
+			}
+		}
+
+		if sourceType == metric.ATTRIBUTE {
+			attributes = append(attributes, attribute.Attr(column, fmt.Sprint(value)))
+			continue
+		}
+		definition[column] = []interface{}{column, sourceType}
+	}
+	return definition, attributes
+}
+
+// populateCustomQueries runs the custom queries of the CustomMetricsConfig file and reports every row as a sample
+// of the node entity. Custom metrics are populated exactly like the built-in ones.
+func populateCustomQueries(e *integration.Entity, db dataSource, args arguments.ArgumentList, state persist.Storer) {
+	queries, err := loadCustomQueries(args.CustomMetricsConfig)
+	if err != nil {
+		log.Error("Error loading custom queries: %v", err)
+		return
+	}
+
+	for _, query := range queries {
+		if !query.isDue(state, args) {
+			log.Debug("Skipping custom query for %s until its interval multiplier is reached", query.EventType)
+			continue
+		}
+
+		rows, err := db.queryRows(query.Query, query.Database)
+		if err != nil {
+			log.Error("Error running custom query for %s: %v", query.EventType, err)
+			continue
+		}
+
+		for _, row := range rows {
+			definition, attributes := query.metricsDefinition(row)
+			ms := infrautils.MetricSet(
+				e,
+				query.EventType,
+				args.Hostname,
+				args.Port,
+				args.RemoteMonitoring,
+				attributes...,
+			)
+			populatePartialMetrics(ms, row, definition, "")
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const customQueriesYAML = `
+queries:
+  - query: SELECT status, COUNT(*) AS jobs, SUM(attempts) AS attempts FROM jobs GROUP BY status
+    event_type: MysqlJobQueueSample
+    database: app
+    metric_types:
+      attempts: rate
+  - query: SELECT COUNT(*) AS pending FROM app.outbox
+    event_type: MysqlOutboxSample
+    interval_multiplier: 3
+`
+
+// rowsDB returns the same rows for every custom query.
+type rowsDB struct {
+	testdb
+	rows    []map[string]interface{}
+	queries []string
+}
+
+func (d *rowsDB) queryRows(query string, schema string, _ ...interface{}) ([]map[string]interface{}, error) {
+	d.queries = append(d.queries, schema+": "+query)
+	return d.rows, nil
+}
+
+func writeCustomQueries(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "custom-queries.yml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadCustomQueries(t *testing.T) {
+	queries, err := loadCustomQueries(writeCustomQueries(t, customQueriesYAML))
+	require.NoError(t, err)
+	require.Len(t, queries, 2)
+	assert.Equal(t, "MysqlJobQueueSample", queries[0].EventType)
+	assert.Equal(t, "app", queries[0].Database)
+	assert.Equal(t, 3, queries[1].IntervalMultiplier)
+}
+
+func TestLoadCustomQueriesValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		err     error
+	}{
+		{"MissingQuery", "queries:\n  - event_type: MysqlCustomSample\n", errCustomQueryMissing},
+		{"MissingEventType", "queries:\n  - query: SELECT 1\n", errCustomEventTypeMissing},
+		{"InvalidMetricType", "queries:\n  - query: SELECT 1 AS one\n    event_type: MysqlCustomSample\n    metric_types:\n      one: counter\n", errInvalidMetricType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadCustomQueries(writeCustomQueries(t, tt.content))
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestCustomQueryMetricsDefinition(t *testing.T) {
+	query := customQuery{MetricTypes: map[string]string{"attempts": "RATE", "code": "attribute"}}
+
+	definition, attributes := query.metricsDefinition(map[string]interface{}{
+		"status":   "failed",
+		"code":     404,
+		"jobs":     12,
+		"attempts": 30,
+	})
+
+	assert.Equal(t, map[string][]interface{}{
+		"jobs":     {"jobs", metric.GAUGE},
+		"attempts": {"attempts", metric.RATE},
+	}, definition)
+	assert.ElementsMatch(t, []string{"status", "code"}, []string{attributes[0].Key, attributes[1].Key})
+}
+
+func TestCustomQueryIntervalMultiplier(t *testing.T) {
+	state := persist.NewInMemoryStore()
+	query := customQuery{Query: "SELECT 1", EventType: "MysqlCustomSample", IntervalMultiplier: 3}
+	args := arguments.ArgumentList{Hostname: "dbhost", Port: 3306}
+
+	var due []bool
+	for range 7 {
+		due = append(due, query.isDue(state, args))
+	}
+	assert.Equal(t, []bool{true, false, false, true, false, false, true}, due)
+}
+
+func TestPopulateCustomQueries(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	db := &rowsDB{rows: []map[string]interface{}{
+		{"status": "queued", "jobs": 3, "attempts": 10},
+		{"status": "failed", "jobs": 1},
+	}}
+	args := arguments.ArgumentList{Port: 3306, CustomMetricsConfig: writeCustomQueries(t, customQueriesYAML)}
+
+	populateCustomQueries(e, db, args, persist.NewInMemoryStore())
+
+	assert.Equal(t, []string{
+		"app: SELECT status, COUNT(*) AS jobs, SUM(attempts) AS attempts FROM jobs GROUP BY status",
+		": SELECT COUNT(*) AS pending FROM app.outbox",
+	}, db.queries)
+
+	// Two rows for each query
+	require.Len(t, e.Metrics, 4)
+	queued := e.Metrics[0]
+	assert.Equal(t, "MysqlJobQueueSample", queued.Metrics["event_type"])
+	assert.Equal(t, "queued", queued.Metrics["status"])
+	assert.Equal(t, 3., queued.Metrics["jobs"])
+	assert.Equal(t, "3306", queued.Metrics["port"])
+	assert.Equal(t, "MysqlOutboxSample", e.Metrics[2].Metrics["event_type"])
+}
+
+func TestDatabaseQueryRows(t *testing.T) {
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	query := "SELECT status, COUNT(*) AS jobs FROM jobs GROUP BY status"
+	mock.ExpectQuery("SELECT DATABASE()").WillReturnRows(sqlmock.NewRows([]string{"DATABASE()"}).AddRow(nil))
+	mock.ExpectExec("USE `app`").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(query).WillReturnRows(sqlmock.NewRows([]string{"status", "jobs"}).
+		AddRow("queued", "3").
+		AddRow(nil, "1"))
+
+	rows, err := (&database{source: db}).queryRows(query, "app")
+	require.NoError(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{"status": "queued", "jobs": 3},
+		{"jobs": 1},
+	}, rows)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
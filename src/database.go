@@ -1,41 +1,50 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	dbutils "github.com/newrelic/nri-mysql/src/dbutils"
 )
 
 type dataSource interface {
-	close()
+	ping() error
 	query(string) (map[string]interface{}, error)
+	queryRows(query string, schema string, args ...interface{}) ([]map[string]interface{}, error)
 	getBackupQuery() string
 }
 
+// pingTimeout bounds the time spent establishing and checking a connection to the server.
+const pingTimeout = 10 * time.Second
+
 type database struct {
-	source *sql.DB
+	source  *sql.DB
+	retrier *dbutils.Retrier
 }
 
 /*
-openSQLDB function creates and returns a connection using the database/sql package for basic SQL database interactions.
+newDatabase returns a dataSource running queries on the connection pool of the target using the database/sql package.
 It provides methods like Query, QueryRow, Exec, etc., that facilitate executing SQL queries and commands.
-This package is well-suited for applications needing standard SQL database operations.
+The pool is owned by the dbutils.ConnectionManager, which is in charge of closing it and retrying transient errors.
 */
-func openSQLDB(dsn string) (dataSource, error) {
-	source, err := sql.Open("mysql", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("error opening %s: %v", dsn, err)
-	}
-	db := database{
-		source: source,
+func newDatabase(conn *dbutils.ConnectionManager) dataSource {
+	return &database{
+		source:  conn.DB(),
+		retrier: conn.Retrier(),
 	}
-	return &db, nil
 }
 
-func (db *database) close() {
-	db.source.Close()
+// ping verifies the server is reachable, establishing a new connection if none is open yet.
+func (db *database) ping() error {
+	return db.retrier.Do(context.Background(), func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), pingTimeout)
+		defer cancel()
+		return db.source.PingContext(ctx)
+	})
 }
 
 // getBackupQuery returns the appropriate backup metrics query based on database version
@@ -54,7 +63,12 @@ In this case, each column name is a key, and corresponding value is a map value.
 */
 func (db *database) query(query string) (map[string]interface{}, error) {
 	log.Debug("executing query: " + query)
-	rows, err := db.source.Query(query)
+	var rows *sql.Rows
+	err := db.retrier.Do(context.Background(), func() error {
+		var err error
+		rows, err = db.source.Query(query)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("error executing `%s`: %v", query, err)
 	}
@@ -101,3 +115,76 @@ func (db *database) query(query string) (map[string]interface{}, error) {
 
 	return rawData, nil
 }
+
+/*
+queryRows executes the query with the given arguments and returns every row as a map of column names to values,
+skipping NULL values. When schema is not empty the query runs on a pinned connection using it as default database.
+*/
+func (db *database) queryRows(query string, schema string, args ...interface{}) ([]map[string]interface{}, error) {
+	log.Debug("executing query: " + query)
+
+	var result []map[string]interface{}
+	err := db.retrier.Do(context.Background(), func() error {
+		var err error
+		result, err = db.queryConnRows(query, schema, args...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error executing `%s`: %w", query, err)
+	}
+	return result, nil
+}
+
+func (db *database) queryConnRows(query string, schema string, args ...interface{}) ([]map[string]interface{}, error) {
+	ctx := context.Background()
+	conn, err := db.source.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if schema != "" {
+		restore, err := dbutils.SwitchSchema(ctx, conn, schema)
+		if err != nil {
+			return nil, err
+		}
+		defer restore()
+	}
+
+	rows, err := conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			log.Warn(fmt.Sprintf("error closing rows: %v", err))
+		}
+	}()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, fmt.Errorf("error getting columns from query: %v", err)
+	}
+
+	values := make([]sql.NullString, len(columns))
+	scanArgs := make([]interface{}, len(values))
+	for i := range values {
+		scanArgs[i] = &values[i]
+	}
+
+	var result []map[string]interface{}
+	for rows.Next() {
+		if err := rows.Scan(scanArgs...); err != nil {
+			return nil, fmt.Errorf("error scanning rows[%d]: %v", len(result), err)
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, value := range values {
+			if value.Valid {
+				row[columns[i]] = asValue(value.String)
+			}
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+	utils "github.com/newrelic/nri-mysql/src/query-performance-monitoring/utils"
+)
+
+const (
+	databaseSampleName = "MysqlDatabaseSample"
+	tableSampleName    = "MysqlTableSample"
+)
+
+/*
+scannedTablesQuery selects the base tables outside of the excluded databases, up to a fixed number. The limit is not
+preceded by an ORDER BY, so the server stops reading table statistics once it is reached, keeping the scan bounded
+on servers with a huge number of tables. The database sizes and the biggest tables are computed from this single
+scan. The %s verb is replaced by the placeholders of the excluded databases.
+*/
+const scannedTablesQuery = `SELECT TABLE_SCHEMA AS database_name, TABLE_NAME AS table_name, ENGINE AS engine,
+	TABLE_ROWS AS table_rows, DATA_LENGTH AS data_length, INDEX_LENGTH AS index_length, DATA_FREE AS data_free,
+	AUTO_INCREMENT AS auto_increment
+	FROM information_schema.TABLES
+	WHERE TABLE_TYPE = 'BASE TABLE' AND TABLE_SCHEMA NOT IN (%s)
+	LIMIT ?`
+
+// databaseSizeColumns are the table columns added up into the size of their database.
+var databaseSizeColumns = []string{"data_length", "index_length", "data_free"}
+
+var databaseSizeMetrics = map[string][]interface{}{
+	"db.dataBytes":  {"data_length", metric.GAUGE},
+	"db.indexBytes": {"index_length", metric.GAUGE},
+	"db.freeBytes":  {"data_free", metric.GAUGE},
+	"db.tableCount": {"table_count", metric.GAUGE},
+}
+
+var tableSizeMetrics = map[string][]interface{}{
+	"table.dataBytes":  {"data_length", metric.GAUGE},
+	"table.indexBytes": {"index_length", metric.GAUGE},
+	"table.freeBytes":  {"data_free", metric.GAUGE},
+	"table.rows":       {"table_rows", metric.GAUGE},
+}
+
+// tableAutoIncrementMetrics are only reported by tables having an AUTO_INCREMENT column.
+var tableAutoIncrementMetrics = map[string][]interface{}{
+	"table.autoIncrement": {"auto_increment", metric.GAUGE},
+}
+
+// populateDatabaseMetrics reports the size of every database as a MysqlDatabaseSample of its database entity and,
+// when enabled, the biggest tables as MysqlTableSample. Databases listed in ExcludedPerformanceDatabases are skipped.
+func populateDatabaseMetrics(i *integration.Integration, db dataSource, args arguments.ArgumentList) {
+	excludedDatabases := utils.GetExcludedDatabases(args.ExcludedPerformanceDatabases)
+	queryArgs := append(utils.ConvertToInterfaceSlice(excludedDatabases), args.DatabaseMetricsMaxTables)
+
+	tables, err := db.queryRows(fmt.Sprintf(scannedTablesQuery, placeholders(len(excludedDatabases))), "", queryArgs...)
+	if err != nil {
+		log.Warn("Can't get database size metrics: %v", err)
+		return
+	}
+	if len(tables) >= args.DatabaseMetricsMaxTables {
+		log.Warn("Only the first %d tables were scanned for database metrics, set DATABASE_METRICS_MAX_TABLES to scan more", args.DatabaseMetricsMaxTables)
+	}
+
+	for _, row := range databaseSizes(tables) {
+		databaseName := fmt.Sprint(row["database_name"])
+		ms, err := databaseMetricSet(i, args, databaseSampleName, databaseName)
+		if err != nil {
+			log.Warn("Can't create entity for database %s: %v", databaseName, err)
+			continue
+		}
+		populatePartialMetrics(ms, row, availableMetrics(databaseSizeMetrics, row), "")
+	}
+
+	if args.TableMetricsLimit <= 0 {
+		return
+	}
+
+	for _, row := range biggestTables(tables, args.TableMetricsLimit) {
+		databaseName := fmt.Sprint(row["database_name"])
+		ms, err := databaseMetricSet(i, args, tableSampleName, databaseName,
+			attribute.Attr("table", fmt.Sprint(row["table_name"])),
+			attribute.Attr("engine", fmt.Sprint(row["engine"])),
+		)
+		if err != nil {
+			log.Warn("Can't create entity for database %s: %v", databaseName, err)
+			continue
+		}
+		populatePartialMetrics(ms, row, tableSizeMetrics, "")
+		if _, ok := row["auto_increment"]; ok {
+			populatePartialMetrics(ms, row, tableAutoIncrementMetrics, "")
+		}
+	}
+}
+
+// databaseSizes adds up the scanned tables of every database, in the order the databases were first scanned.
+func databaseSizes(tables []map[string]interface{}) []map[string]interface{} {
+	var databases []map[string]interface{}
+	byName := map[string]map[string]interface{}{}
+	for _, table := range tables {
+		name := fmt.Sprint(table["database_name"])
+		database, ok := byName[name]
+		if !ok {
+			database = map[string]interface{}{"database_name": name, "table_count": 0}
+			byName[name] = database
+			databases = append(databases, database)
+		}
+
+		database["table_count"] = database["table_count"].(int) + 1
+		for _, column := range databaseSizeColumns {
+			// Columns are NULL, and missing from the row, for tables whose statistics can't be read
+			if size, ok := tableBytes(table, column); ok {
+				total, _ := database[column].(float64)
+				database[column] = total + size
+			}
+		}
+	}
+	return databases
+}
+
+// biggestTables returns up to limit scanned tables, the biggest by data and index size first.
+func biggestTables(tables []map[string]interface{}, limit int) []map[string]interface{} {
+	size := func(table map[string]interface{}) float64 {
+		data, _ := tableBytes(table, "data_length")
+		index, _ := tableBytes(table, "index_length")
+		return data + index
+	}
+
+	biggest := slices.Clone(tables)
+	sort.SliceStable(biggest, func(a, b int) bool { return size(biggest[a]) > size(biggest[b]) })
+	return biggest[:min(limit, len(biggest))]
+}
+
+// tableBytes returns a numeric column of a scanned table.
+func tableBytes(table map[string]interface{}, column string) (float64, bool) {
+	value, ok := table[column]
+	if !ok {
+		return 0, false
+	}
+	size, err := strconv.ParseFloat(fmt.Sprint(value), 64)
+	return size, err == nil
+}
+
+// databaseMetricSet returns a new metric set of the entity of a database, identified by the database attribute.
+func databaseMetricSet(i *integration.Integration, args arguments.ArgumentList, eventType, databaseName string, attributes ...attribute.Attribute) (*metric.Set, error) {
+	e, err := infrautils.CreateDatabaseEntity(i, args.Hostname, args.Port, databaseName)
+	if err != nil {
+		return nil, err
+	}
+	return infrautils.MetricSet(
+		e,
+		eventType,
+		args.Hostname,
+		args.Port,
+		args.RemoteMonitoring,
+		append([]attribute.Attribute{attribute.Attr("database", databaseName)}, attributes...)...,
+	), nil
+}
+
+// placeholders returns n comma separated query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
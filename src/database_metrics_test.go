@@ -0,0 +1,128 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// schemaDB answers the scan of information_schema.TABLES.
+type schemaDB struct {
+	testdb
+	tables []map[string]interface{}
+	args   [][]interface{}
+}
+
+func (d *schemaDB) queryRows(_ string, _ string, args ...interface{}) ([]map[string]interface{}, error) {
+	d.args = append(d.args, args)
+	return d.tables, nil
+}
+
+func TestPopulateDatabaseMetrics(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	db := &schemaDB{
+		tables: []map[string]interface{}{
+			{"database_name": "app", "table_name": "events", "engine": "InnoDB", "table_rows": 5, "data_length": 1024,
+				"index_length": 0, "data_free": 0},
+			{"database_name": "app", "table_name": "orders", "engine": "InnoDB", "table_rows": 10, "data_length": 3072,
+				"index_length": 1024, "data_free": 0, "auto_increment": 11},
+		},
+	}
+	args := arguments.ArgumentList{Hostname: "dbhost", Port: 3306, RemoteMonitoring: true,
+		ExcludedPerformanceDatabases: `["audit"]`, DatabaseMetricsMaxTables: 100, TableMetricsLimit: 5}
+
+	populateDatabaseMetrics(i, db, args)
+
+	// The tables are scanned once, excluding the system databases and the configured ones, up to the scan limit
+	require.Len(t, db.args, 1)
+	assert.Contains(t, db.args[0], "audit")
+	assert.Contains(t, db.args[0], "information_schema")
+	assert.Equal(t, 100, db.args[0][len(db.args[0])-1])
+
+	require.Len(t, i.Entities, 1)
+	e := i.Entities[0]
+	assert.Equal(t, "dbhost:3306/app", e.Metadata.Name)
+	assert.Equal(t, "database", e.Metadata.Namespace)
+	require.Len(t, e.Metrics, 3)
+
+	database := e.Metrics[0]
+	assert.Equal(t, databaseSampleName, database.Metrics["event_type"])
+	assert.Equal(t, "app", database.Metrics["database"])
+	assert.Equal(t, 4096., database.Metrics["db.dataBytes"])
+	assert.Equal(t, 1024., database.Metrics["db.indexBytes"])
+	assert.Equal(t, 2., database.Metrics["db.tableCount"])
+
+	// The biggest table is reported first
+	orders := e.Metrics[1]
+	assert.Equal(t, tableSampleName, orders.Metrics["event_type"])
+	assert.Equal(t, "orders", orders.Metrics["table"])
+	assert.Equal(t, "InnoDB", orders.Metrics["engine"])
+	assert.Equal(t, 10., orders.Metrics["table.rows"])
+	assert.Equal(t, 11., orders.Metrics["table.autoIncrement"])
+	assert.Equal(t, "events", e.Metrics[2].Metrics["table"])
+	assert.NotContains(t, e.Metrics[2].Metrics, "table.autoIncrement")
+}
+
+func TestPopulateDatabaseMetricsWithoutTables(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+
+	db := &schemaDB{tables: []map[string]interface{}{{"database_name": "app", "table_name": "orders", "data_length": 1024}}}
+	args := arguments.ArgumentList{Hostname: "dbhost", Port: 3306, ExcludedPerformanceDatabases: "[]", DatabaseMetricsMaxTables: 100}
+
+	populateDatabaseMetrics(i, db, args)
+
+	// Table metrics are disabled by default
+	assert.Len(t, db.args, 1)
+	require.Len(t, i.Entities, 1)
+	require.Len(t, i.Entities[0].Metrics, 1)
+	assert.Equal(t, databaseSampleName, i.Entities[0].Metrics[0].Metrics["event_type"])
+}
+
+func TestPlaceholders(t *testing.T) {
+	assert.Equal(t, "", placeholders(0))
+	assert.Equal(t, "?", placeholders(1))
+	assert.Equal(t, "?, ?, ?", placeholders(3))
+}
+
+func TestScannedTablesQueryIsBounded(t *testing.T) {
+	// Ordering before the limit would read the statistics of every table
+	assert.NotContains(t, scannedTablesQuery, "ORDER BY")
+	assert.True(t, strings.HasSuffix(scannedTablesQuery, "LIMIT ?"))
+}
+
+func TestDatabaseSizes(t *testing.T) {
+	databases := databaseSizes([]map[string]interface{}{
+		{"database_name": "shop", "data_length": 2048, "index_length": 512, "data_free": 100},
+		{"database_name": "app", "data_length": 1024, "index_length": 0},
+		{"database_name": "shop", "data_length": 1024, "index_length": 512, "data_free": 0},
+	})
+
+	assert.Equal(t, []map[string]interface{}{
+		{"database_name": "shop", "table_count": 2, "data_length": 3072., "index_length": 1024., "data_free": 100.},
+		{"database_name": "app", "table_count": 1, "data_length": 1024., "index_length": 0.},
+	}, databases)
+}
+
+func TestBiggestTables(t *testing.T) {
+	tables := []map[string]interface{}{
+		{"table_name": "small", "data_length": 10, "index_length": 0},
+		{"table_name": "big", "data_length": 100, "index_length": 50},
+		{"table_name": "unknown"},
+		{"table_name": "medium", "data_length": 40, "index_length": 40},
+	}
+
+	biggest := biggestTables(tables, 2)
+
+	require.Len(t, biggest, 2)
+	assert.Equal(t, "big", biggest[0]["table_name"])
+	assert.Equal(t, "medium", biggest[1]["table_name"])
+	assert.Equal(t, "small", tables[0]["table_name"])
+	assert.Len(t, biggestTables(tables, 10), 4)
+}
@@ -11,16 +11,34 @@ import (
 )
 
 // GenerateDSN generates a data source name (DSN) string for connecting to a MySQL database.
+// The password is resolved through the configured PasswordSource. IAM authentication always enforces TLS.
+// Option files and login paths are applied once per target by NewConnectionManager, which also reports the
+// failures of the password source.
 func GenerateDSN(args arguments.ArgumentList, database string) string {
+	// A failed password source leaves the password empty rather than falling back to the default argument
+	password, _ := resolvePassword(args)
+
 	query := url.Values{}
 	if args.OldPasswords {
 		query.Add("allowOldPasswords", "true")
 	}
-	if args.EnableTLS {
-		query.Add("tls", "true")
+	if args.EnableIAMAuth {
+		// IAM authentication tokens are sent in clear text, which RDS only accepts over TLS
+		query.Add("allowCleartextPasswords", "true")
 	}
-	if args.InsecureSkipVerify {
-		query.Add("tls", "skip-verify")
+	switch {
+	case hasCustomTLS(args):
+		// The configuration is registered by RegisterTLSConfig before connecting
+		query.Add("tls", TLSConfigName(args))
+	case args.EnableIAMAuth && !args.InsecureSkipVerify:
+		query.Add("tls", "true")
+	default:
+		if args.EnableTLS {
+			query.Add("tls", "true")
+		}
+		if args.InsecureSkipVerify {
+			query.Add("tls", "skip-verify")
+		}
 	}
 	extraArgsMap, err := url.ParseQuery(args.ExtraConnectionURLArgs)
 	if err == nil {
@@ -32,13 +50,13 @@ func GenerateDSN(args arguments.ArgumentList, database string) string {
 	}
 	if args.Socket != "" {
 		log.Debug("Socket parameter is defined, ignoring host and port parameters")
-		return fmt.Sprintf("%s:%s@unix(%s)/%s?%s", args.Username, args.Password, args.Socket, determineDatabase(args, database), query.Encode())
+		return fmt.Sprintf("%s:%s@unix(%s)/%s?%s", args.Username, password, args.Socket, determineDatabase(args, database), query.Encode())
 	}
 
 	// Convert hostname and port to DSN address format
 	mysqlURL := net.JoinHostPort(args.Hostname, strconv.Itoa(args.Port))
 
-	return fmt.Sprintf("%s:%s@tcp(%s)/%s?%s", args.Username, args.Password, mysqlURL, determineDatabase(args, database), query.Encode())
+	return fmt.Sprintf("%s:%s@tcp(%s)/%s?%s", args.Username, password, mysqlURL, determineDatabase(args, database), query.Encode())
 }
 
 // determineDatabase determines which database name to use for the DSN.
@@ -0,0 +1,100 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+// ConnectionManager owns the single connection pool used by every collector of a target, so that core metrics,
+// query performance monitoring and execution plans do not open connections of their own.
+type ConnectionManager struct {
+	source  *sqlx.DB
+	retrier *Retrier
+}
+
+// NewConnectionManager opens the connection pool of the target described by args. Connections are established lazily
+// with the session settings of the Session* arguments, and the number of open and idle connections is capped by
+// the MaxOpenConnections and MaxIdleConnections arguments. Transient connection errors are retried as configured
+// by the ConnectRetry* arguments. Credentials, socket and SSL settings not set explicitly are read once from the
+// configured login path and option file, and a failure of the password source fails the target.
+func NewConnectionManager(args arguments.ArgumentList) (*ConnectionManager, error) {
+	args = withOptionFiles(args)
+	if _, err := resolvePassword(args); err != nil {
+		return nil, err
+	}
+	if err := RegisterTLSConfig(args); err != nil {
+		return nil, err
+	}
+
+	config, err := mysql.ParseDSN(GenerateDSN(args, ""))
+	if err != nil {
+		return nil, fmt.Errorf("error parsing DSN: %w", err)
+	}
+	connector, err := mysql.NewConnector(config)
+	if err != nil {
+		return nil, fmt.Errorf("error opening connection pool: %w", err)
+	}
+
+	// Every connection of the pool applies the monitoring session profile when it is established
+	source := sqlx.NewDb(sql.OpenDB(newSessionConnector(connector, args)), "mysql")
+	source.SetMaxOpenConns(args.MaxOpenConnections)
+	source.SetMaxIdleConns(maxIdleConnections(args))
+
+	return &ConnectionManager{
+		source:  source,
+		retrier: NewRetrier(args),
+	}, nil
+}
+
+// maxIdleConnections returns the idle connections kept by the pool, which can't exceed the open connections.
+// A MaxOpenConnections of 0 means unlimited, so it only bounds the idle connections when set.
+func maxIdleConnections(args arguments.ArgumentList) int {
+	if args.MaxOpenConnections > 0 {
+		return min(args.MaxIdleConnections, args.MaxOpenConnections)
+	}
+	return args.MaxIdleConnections
+}
+
+// DB returns the underlying database/sql handle.
+func (m *ConnectionManager) DB() *sql.DB {
+	return m.source.DB
+}
+
+// Close closes the connection pool.
+func (m *ConnectionManager) Close() {
+	if err := m.source.Close(); err != nil {
+		log.Warn("Error closing connection pool: %v", err)
+	}
+}
+
+// Retrier returns the Retrier shared by every query run on the pool.
+func (m *ConnectionManager) Retrier() *Retrier {
+	return m.retrier
+}
+
+// QueryX runs query on the pool, retrying transient connection errors.
+func (m *ConnectionManager) QueryX(query string) (*sqlx.Rows, error) {
+	return m.QueryxContext(context.Background(), query)
+}
+
+// QueryxContext runs query with args on the pool, retrying transient connection errors until ctx is done.
+func (m *ConnectionManager) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	var rows *sqlx.Rows
+	err := m.retrier.Do(ctx, func() error {
+		var err error
+		rows, err = m.source.QueryxContext(ctx, query, args...)
+		return err
+	})
+	return rows, err
+}
+
+// Connx pins a connection of the pool, for statements that must run in the same session.
+func (m *ConnectionManager) Connx(ctx context.Context) (*sqlx.Conn, error) {
+	return m.source.Connx(ctx)
+}
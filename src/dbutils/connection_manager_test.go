@@ -0,0 +1,37 @@
+package dbutils
+
+import (
+	"testing"
+
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewConnectionManagerCapsConnections(t *testing.T) {
+	args := arguments.ArgumentList{Hostname: "localhost", Port: 3306, Username: "root", Password: "secret",
+		MaxOpenConnections: 3, MaxIdleConnections: 5}
+
+	manager, err := NewConnectionManager(args)
+	require.NoError(t, err)
+	defer manager.Close()
+
+	// Opening the pool does not connect to the server
+	stats := manager.DB().Stats()
+	assert.Equal(t, 3, stats.MaxOpenConnections)
+	assert.Equal(t, 0, stats.OpenConnections)
+}
+
+func TestMaxIdleConnections(t *testing.T) {
+	assert.Equal(t, 3, maxIdleConnections(arguments.ArgumentList{MaxOpenConnections: 3, MaxIdleConnections: 5}))
+	assert.Equal(t, 2, maxIdleConnections(arguments.ArgumentList{MaxOpenConnections: 3, MaxIdleConnections: 2}))
+	// Unlimited open connections keep the configured idle connections
+	assert.Equal(t, 5, maxIdleConnections(arguments.ArgumentList{MaxOpenConnections: 0, MaxIdleConnections: 5}))
+}
+
+func TestNewConnectionManagerFailsOnInvalidTLS(t *testing.T) {
+	args := arguments.ArgumentList{Hostname: "localhost", Port: 3306, TLSCaFile: "/nonexistent/ca.pem"}
+
+	_, err := NewConnectionManager(args)
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package dbutils
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Error classes reported when a connection to the MySQL server fails.
+const (
+	ErrorClassAuth    = "auth"
+	ErrorClassNetwork = "network"
+	ErrorClassTLS     = "tls"
+	ErrorClassTimeout = "timeout"
+	ErrorClassServer  = "server"
+	ErrorClassUnknown = "unknown"
+)
+
+// authErrorNumbers lists the server error codes returned when the credentials are rejected.
+var authErrorNumbers = map[uint16]struct{}{
+	1044: {}, // ER_DBACCESS_DENIED_ERROR
+	1045: {}, // ER_ACCESS_DENIED_ERROR
+	1251: {}, // ER_NOT_SUPPORTED_AUTH_MODE
+	1698: {}, // ER_ACCESS_DENIED_NO_PASSWORD_ERROR
+	1820: {}, // ER_MUST_CHANGE_PASSWORD
+	1862: {}, // ER_MUST_CHANGE_PASSWORD_LOGIN
+}
+
+// authDriverErrors lists the driver errors raised when the authentication handshake cannot be completed.
+var authDriverErrors = []error{
+	mysql.ErrCleartextPassword,
+	mysql.ErrNativePassword,
+	mysql.ErrOldPassword,
+	mysql.ErrUnknownPlugin,
+}
+
+// ClassifyError returns the class of a connection or query error: auth, network, tls, timeout, server or unknown.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		if _, ok := authErrorNumbers[mysqlErr.Number]; ok {
+			return ErrorClassAuth
+		}
+		return ErrorClassServer
+	}
+	for _, authErr := range authDriverErrors {
+		if errors.Is(err, authErr) {
+			return ErrorClassAuth
+		}
+	}
+
+	if isTLSError(err) {
+		return ErrorClassTLS
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, os.ErrDeadlineExceeded) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) {
+		return ErrorClassTimeout
+	}
+
+	var opErr *net.OpError
+	var dnsErr *net.DNSError
+	if errors.As(err, &opErr) || errors.As(err, &dnsErr) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, driver.ErrBadConn) || errors.Is(err, mysql.ErrInvalidConn) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return ErrorClassNetwork
+	}
+
+	return ErrorClassUnknown
+}
+
+func isTLSError(err error) bool {
+	var recordErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	var verificationErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidErr x509.CertificateInvalidError
+
+	return errors.Is(err, mysql.ErrNoTLS) ||
+		errors.As(err, &recordErr) ||
+		errors.As(err, &alertErr) ||
+		errors.As(err, &verificationErr) ||
+		errors.As(err, &unknownAuthorityErr) ||
+		errors.As(err, &hostnameErr) ||
+		errors.As(err, &invalidErr)
+}
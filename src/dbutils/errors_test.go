@@ -0,0 +1,51 @@
+package dbutils
+
+import (
+	"context"
+	"crypto/x509"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+)
+
+var errSomething = errors.New("something went wrong")
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"Nil", nil, ""},
+		{"AccessDenied", &mysql.MySQLError{Number: 1045, Message: "Access denied for user"}, ErrorClassAuth},
+		{"WrappedAccessDenied", fmt.Errorf("error querying: %w", &mysql.MySQLError{Number: 1044}), ErrorClassAuth},
+		{"CleartextPassword", mysql.ErrCleartextPassword, ErrorClassAuth},
+		{"TooManyConnections", &mysql.MySQLError{Number: 1040}, ErrorClassServer},
+		{"NoTLS", mysql.ErrNoTLS, ErrorClassTLS},
+		{"UnknownAuthority", x509.UnknownAuthorityError{}, ErrorClassTLS},
+		{"DeadlineExceeded", context.DeadlineExceeded, ErrorClassTimeout},
+		{"DialTimeout", &net.OpError{Op: "dial", Err: timeoutError{}}, ErrorClassTimeout},
+		{"ConnectionRefused", &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}, ErrorClassNetwork},
+		{"BadConn", driver.ErrBadConn, ErrorClassNetwork},
+		{"InvalidConn", mysql.ErrInvalidConn, ErrorClassNetwork},
+		{"Unknown", errSomething, ErrorClassUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ClassifyError(tt.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
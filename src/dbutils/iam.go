@@ -0,0 +1,207 @@
+package dbutils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+const (
+	// iamTokenExpiry is the validity of an RDS IAM authentication token, the maximum accepted by RDS.
+	iamTokenExpiry = 15 * time.Minute
+	iamService     = "rds-db"
+	sigV4Algorithm = "AWS4-HMAC-SHA256"
+	sigV4TimeFmt   = "20060102T150405Z"
+	sigV4DateFmt   = "20060102"
+	// emptyPayloadHash is the SHA-256 of an empty request body.
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+var (
+	errAWSRegionMissing      = errors.New("AWS region is required for IAM authentication")
+	errAWSCredentialsMissing = errors.New("no AWS credentials found in the environment or shared credentials file")
+)
+
+// now is replaced in tests to sign tokens at a fixed time.
+var now = time.Now
+
+// awsCredentials holds the AWS access key used to sign IAM authentication tokens.
+type awsCredentials struct {
+	accessKeyID     string
+	secretAccessKey string
+	sessionToken    string
+}
+
+// iamTokenSource generates an RDS IAM authentication token used as the password.
+// The token is a SigV4 presigned `connect` request, so it is generated locally without calling AWS.
+type iamTokenSource struct {
+	endpoint string
+	region   string
+	username string
+	profile  string
+}
+
+func newIAMTokenSource(args arguments.ArgumentList) iamTokenSource {
+	region := args.AWSRegion
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	if region == "" {
+		region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	profile := args.AWSProfile
+	if profile == "" {
+		profile = os.Getenv("AWS_PROFILE")
+	}
+	if profile == "" {
+		profile = "default"
+	}
+
+	return iamTokenSource{
+		endpoint: net.JoinHostPort(args.Hostname, strconv.Itoa(args.Port)),
+		region:   region,
+		username: args.Username,
+		profile:  profile,
+	}
+}
+
+func (s iamTokenSource) Name() string {
+	return "IAM token " + s.username + "@" + s.endpoint
+}
+
+func (s iamTokenSource) Password() (string, error) {
+	if s.region == "" {
+		return "", errAWSRegionMissing
+	}
+	creds, err := loadAWSCredentials(s.profile)
+	if err != nil {
+		return "", err
+	}
+	return buildIAMAuthToken(s.endpoint, s.region, s.username, creds, now().UTC()), nil
+}
+
+// buildIAMAuthToken returns the presigned `connect` URL, without scheme, that RDS accepts as password.
+func buildIAMAuthToken(endpoint, region, username string, creds awsCredentials, signTime time.Time) string {
+	query := url.Values{}
+	query.Set("Action", "connect")
+	query.Set("DBUser", username)
+
+	presigned := presignURL(endpoint, "/", query, creds, region, iamService, emptyPayloadHash, iamTokenExpiry, signTime)
+	return strings.TrimPrefix(presigned, "https://")
+}
+
+// presignURL signs a GET request with AWS Signature Version 4 using query string parameters.
+func presignURL(host, path string, query url.Values, creds awsCredentials, region, service, payloadHash string,
+	expiry time.Duration, signTime time.Time) string {
+	date := signTime.Format(sigV4DateFmt)
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+
+	query.Set("X-Amz-Algorithm", sigV4Algorithm)
+	query.Set("X-Amz-Credential", creds.accessKeyID+"/"+scope)
+	query.Set("X-Amz-Date", signTime.Format(sigV4TimeFmt))
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expiry.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "host")
+	if creds.sessionToken != "" {
+		query.Set("X-Amz-Security-Token", creds.sessionToken)
+	}
+	canonicalQuery := canonicalQueryString(query)
+
+	canonicalRequest := strings.Join([]string{
+		"GET",
+		path,
+		canonicalQuery,
+		"host:" + host + "\n",
+		"host",
+		payloadHash,
+	}, "\n")
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		signTime.Format(sigV4TimeFmt),
+		scope,
+		hexSHA256(canonicalRequest),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+creds.secretAccessKey), date)
+	for _, part := range []string{region, service, "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	return fmt.Sprintf("https://%s%s?%s&X-Amz-Signature=%s", host, path, canonicalQuery, signature)
+}
+
+// canonicalQueryString sorts and encodes the query parameters as required by SigV4, escaping spaces as %20.
+func canonicalQueryString(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, sigV4Escape(key)+"="+sigV4Escape(value))
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+func sigV4Escape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func hexSHA256(data string) string {
+	sum := sha256.Sum256([]byte(data))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadAWSCredentials reads the AWS credentials from the standard environment variables or, otherwise,
+// from the given profile of the shared credentials file.
+func loadAWSCredentials(profile string) (awsCredentials, error) {
+	creds := awsCredentials{
+		accessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if creds.accessKeyID != "" && creds.secretAccessKey != "" {
+		return creds, nil
+	}
+
+	path := os.Getenv("AWS_SHARED_CREDENTIALS_FILE")
+	if path == "" {
+		path = expandHome("~/.aws/credentials")
+	}
+	// The shared credentials file uses the same INI layout as MySQL option files
+	groups, err := readOptionFile(path, maxIncludeDepth)
+	if err != nil {
+		return awsCredentials{}, fmt.Errorf("%w: %v", errAWSCredentialsMissing, err)
+	}
+	options := groups[strings.ToLower(profile)]
+	creds = awsCredentials{
+		accessKeyID:     options["aws-access-key-id"],
+		secretAccessKey: options["aws-secret-access-key"],
+		sessionToken:    options["aws-session-token"],
+	}
+	if creds.accessKeyID == "" || creds.secretAccessKey == "" {
+		return awsCredentials{}, fmt.Errorf("%w: profile %s", errAWSCredentialsMissing, profile)
+	}
+	return creds, nil
+}
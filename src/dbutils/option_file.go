@@ -0,0 +1,346 @@
+package dbutils
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+// maxIncludeDepth bounds nested !include directives to protect against include cycles.
+const maxIncludeDepth = 10
+
+// loginFileKeyOffset is the position of the obfuscation key in a .mylogin.cnf file, after 4 unused bytes.
+const loginFileKeyOffset = 4
+
+// loginFileKeyLength is the length of the obfuscation key stored in a .mylogin.cnf file.
+const loginFileKeyLength = 20
+
+var (
+	errIncludeDepthExceeded = errors.New("maximum !include depth exceeded")
+	errInvalidLoginFile     = errors.New("invalid login path file")
+)
+
+// optionGroups holds the options of a MySQL option file indexed by group and option name.
+type optionGroups map[string]map[string]string
+
+// withOptionFiles returns a copy of args where the credentials, socket and SSL settings not set explicitly
+// are read from the configured login path and option file group. Login path values take precedence, as
+// they do for the mysql client. The files are read once: the returned arguments no longer reference them.
+func withOptionFiles(args arguments.ArgumentList) arguments.ArgumentList {
+	if args.LoginPath != "" {
+		groups, err := readLoginPathFile(loginPathFile())
+		if err != nil {
+			log.Warn("Could not read login path %s: %v", args.LoginPath, err)
+		} else {
+			args = applyOptions(args, loginPathOptions(groups, args.LoginPath))
+		}
+	}
+
+	if args.OptionFile != "" {
+		groups, err := readOptionFile(expandHome(args.OptionFile), 0)
+		if err != nil {
+			log.Warn("Could not read option file %s: %v", args.OptionFile, err)
+		} else {
+			args = applyOptions(args, groups[strings.ToLower(args.OptionFileGroup)])
+		}
+	}
+
+	args.LoginPath = ""
+	args.OptionFile = ""
+	return args
+}
+
+// loginPathOptions returns the options of a login path merged over the ones of the [client] group, which the mysql
+// client reads before any login path.
+func loginPathOptions(groups optionGroups, loginPath string) map[string]string {
+	options := map[string]string{}
+	for _, group := range []string{"client", strings.ToLower(loginPath)} {
+		for name, value := range groups[group] {
+			options[name] = value
+		}
+	}
+	return options
+}
+
+// applyOptions fills the arguments still holding their default value with the given options.
+// The socket is only used for local targets, since it takes precedence over the hostname and port.
+func applyOptions(args arguments.ArgumentList, options map[string]string) arguments.ArgumentList {
+	if len(options) == 0 {
+		return args
+	}
+
+	if value, ok := options["user"]; ok {
+		if isArgumentSet("Username", args.Username) {
+			log.Debug("Ignoring the user of the option file, the username is set explicitly")
+		} else {
+			args.Username = value
+		}
+	}
+	if value, ok := options["password"]; ok {
+		if isArgumentSet("Password", args.Password) {
+			log.Debug("Ignoring the password of the option file, the password is set explicitly")
+		} else {
+			args.Password = value
+		}
+	}
+	if value, ok := options["socket"]; ok && args.Socket == "" {
+		if isLocalHost(args.Hostname) {
+			args.Socket = value
+		} else {
+			log.Debug("Ignoring the socket of the option file for the remote host %s", args.Hostname)
+		}
+	}
+	if value, ok := options["ssl-ca"]; ok && args.TLSCaFile == "" {
+		args.TLSCaFile = value
+	}
+	if value, ok := options["ssl-cert"]; ok && args.TLSCertFile == "" {
+		args.TLSCertFile = value
+	}
+	if value, ok := options["ssl-key"]; ok && args.TLSKeyFile == "" {
+		args.TLSKeyFile = value
+	}
+	if value, ok := options["ssl-mode"]; ok && !args.EnableTLS && !args.InsecureSkipVerify {
+		switch strings.ToUpper(value) {
+		case "REQUIRED":
+			args.InsecureSkipVerify = true
+		case "VERIFY_CA", "VERIFY_IDENTITY":
+			args.EnableTLS = true
+		}
+	}
+	return args
+}
+
+// isArgumentSet reports whether an ArgumentList field was set explicitly: either its value differs from the
+// declared default or, when it holds the default, its environment variable or flag was set.
+func isArgumentSet(field string, value string) bool {
+	if value == "" {
+		return false
+	}
+	structField, ok := reflect.TypeOf(arguments.ArgumentList{}).FieldByName(field)
+	if !ok || structField.Tag.Get("default") != value {
+		return true
+	}
+	return arguments.IsArgumentSet(strings.ToLower(field))
+}
+
+// isLocalHost reports whether hostname designates the local host, which a socket can reach.
+func isLocalHost(hostname string) bool {
+	switch strings.ToLower(hostname) {
+	case "", "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// readOptionFile parses a MySQL option file, following !include and !includedir directives.
+func readOptionFile(path string, depth int) (optionGroups, error) {
+	if depth > maxIncludeDepth {
+		return nil, fmt.Errorf("%w: %s", errIncludeDepthExceeded, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseOptions(data, path, depth)
+}
+
+// parseOptions parses the content of an option file located at path.
+func parseOptions(data []byte, path string, depth int) (optionGroups, error) {
+	groups := optionGroups{}
+	group := ""
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";"):
+			continue
+		case strings.HasPrefix(line, "!includedir"):
+			dir := resolveIncludePath(path, strings.TrimSpace(strings.TrimPrefix(line, "!includedir")))
+			included, err := readOptionDir(dir, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			groups.merge(included)
+		case strings.HasPrefix(line, "!include"):
+			file := resolveIncludePath(path, strings.TrimSpace(strings.TrimPrefix(line, "!include")))
+			included, err := readOptionFile(file, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			groups.merge(included)
+		case strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]"):
+			group = strings.ToLower(strings.TrimSpace(line[1 : len(line)-1]))
+		case group != "":
+			name, value := parseOptionLine(line)
+			if groups[group] == nil {
+				groups[group] = map[string]string{}
+			}
+			groups[group][name] = value
+		}
+	}
+	return groups, scanner.Err()
+}
+
+// readOptionDir parses every option file of a directory in lexical order, as the mysql client does.
+func readOptionDir(dir string, depth int) (optionGroups, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		ext := filepath.Ext(entry.Name())
+		if ext == ".cnf" || (runtime.GOOS == "windows" && ext == ".ini") {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	groups := optionGroups{}
+	for _, name := range names {
+		included, err := readOptionFile(filepath.Join(dir, name), depth)
+		if err != nil {
+			return nil, err
+		}
+		groups.merge(included)
+	}
+	return groups, nil
+}
+
+// parseOptionLine splits an `option = value` line, normalising the option name and unquoting the value.
+func parseOptionLine(line string) (string, string) {
+	name, value, _ := strings.Cut(line, "=")
+	name = strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "-")
+	value = strings.TrimSpace(value)
+
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+		if end := strings.IndexByte(value[1:], value[0]); end >= 0 {
+			return name, unescapeOptionValue(value[1 : end+1])
+		}
+	}
+	if idx := strings.Index(value, " #"); idx >= 0 {
+		value = strings.TrimSpace(value[:idx])
+	}
+	return name, unescapeOptionValue(value)
+}
+
+func unescapeOptionValue(value string) string {
+	replacer := strings.NewReplacer(`\n`, "\n", `\t`, "\t", `\r`, "\r", `\s`, " ", `\\`, `\`)
+	return replacer.Replace(value)
+}
+
+func (groups optionGroups) merge(other optionGroups) {
+	for group, options := range other {
+		if groups[group] == nil {
+			groups[group] = map[string]string{}
+		}
+		for name, value := range options {
+			groups[group][name] = value
+		}
+	}
+}
+
+// resolveIncludePath resolves an included path relative to the directory of the including file.
+func resolveIncludePath(parent string, path string) string {
+	path = expandHome(path)
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(parent), path)
+}
+
+func expandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// loginPathFile returns the location of the login path file written by mysql_config_editor.
+func loginPathFile() string {
+	if path := os.Getenv("MYSQL_TEST_LOGIN_FILE"); path != "" {
+		return path
+	}
+	if runtime.GOOS == "windows" {
+		return filepath.Join(os.Getenv("APPDATA"), "MySQL", ".mylogin.cnf")
+	}
+	return expandHome("~/.mylogin.cnf")
+}
+
+// readLoginPathFile decrypts a .mylogin.cnf file and parses it as an option file.
+// The file holds an AES-128-ECB key followed by length prefixed encrypted lines.
+func readLoginPathFile(path string) (optionGroups, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := decryptLoginPathFile(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Include directives are not supported in login path files
+	return parseOptions(plain, path, maxIncludeDepth)
+}
+
+func decryptLoginPathFile(data []byte) ([]byte, error) {
+	if len(data) < loginFileKeyOffset+loginFileKeyLength {
+		return nil, errInvalidLoginFile
+	}
+
+	key := make([]byte, aes.BlockSize)
+	for i, b := range data[loginFileKeyOffset : loginFileKeyOffset+loginFileKeyLength] {
+		key[i%aes.BlockSize] ^= b
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var plain bytes.Buffer
+	for offset := loginFileKeyOffset + loginFileKeyLength; offset < len(data); {
+		if offset+4 > len(data) {
+			return nil, errInvalidLoginFile
+		}
+		length := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+		offset += 4
+		if length == 0 || length%aes.BlockSize != 0 || offset+length > len(data) {
+			return nil, fmt.Errorf("%w: chunk of %d bytes", errInvalidLoginFile, length)
+		}
+
+		chunk := make([]byte, length)
+		for i := 0; i < length; i += aes.BlockSize {
+			block.Decrypt(chunk[i:i+aes.BlockSize], data[offset+i:offset+i+aes.BlockSize])
+		}
+		padding := int(chunk[length-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, errInvalidLoginFile
+		}
+		plain.Write(chunk[:length-padding])
+		offset += length
+	}
+	return plain.Bytes(), nil
+}
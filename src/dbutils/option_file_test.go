@@ -0,0 +1,216 @@
+package dbutils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFile(t *testing.T, path string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+func TestReadOptionFile(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "my.cnf"), `# main option file
+[mysqld]
+user = mysql
+
+[client]
+user = monitor
+password = "p@ss # word"
+ssl_ca = /etc/mysql/ca.pem  # inline comment
+
+!include extra.cnf
+!includedir conf.d
+`)
+	writeFile(t, filepath.Join(dir, "extra.cnf"), "[client]\nsocket=/var/run/mysqld/mysqld.sock\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "10-client.cnf"), "[CLIENT]\nuser=first\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "20-client.cnf"), "[client]\nuser=second\n")
+	writeFile(t, filepath.Join(dir, "conf.d", "ignored.txt"), "[client]\nuser=ignored\n")
+
+	groups, err := readOptionFile(filepath.Join(dir, "my.cnf"), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, "mysql", groups["mysqld"]["user"])
+	assert.Equal(t, "second", groups["client"]["user"])
+	assert.Equal(t, "p@ss # word", groups["client"]["password"])
+	assert.Equal(t, "/etc/mysql/ca.pem", groups["client"]["ssl-ca"])
+	assert.Equal(t, "/var/run/mysqld/mysqld.sock", groups["client"]["socket"])
+}
+
+func TestReadOptionFileIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "my.cnf"), "!include my.cnf\n")
+
+	_, err := readOptionFile(filepath.Join(dir, "my.cnf"), 0)
+	assert.ErrorIs(t, err, errIncludeDepthExceeded)
+}
+
+func TestApplyOptionsOnlyOverridesDefaults(t *testing.T) {
+	clearCredentialArguments(t)
+	options := map[string]string{
+		"user":     "monitor",
+		"password": "secret",
+		"socket":   "/tmp/mysql.sock",
+		"ssl-mode": "VERIFY_CA",
+	}
+
+	args := applyOptions(arguments.ArgumentList{Username: "root", Password: "password"}, options)
+	assert.Equal(t, "monitor", args.Username)
+	assert.Equal(t, "secret", args.Password)
+	assert.Equal(t, "/tmp/mysql.sock", args.Socket)
+	assert.True(t, args.EnableTLS)
+
+	args = applyOptions(arguments.ArgumentList{Username: "newrelic", Password: "explicit", Socket: "/other.sock"}, options)
+	assert.Equal(t, "newrelic", args.Username)
+	assert.Equal(t, "explicit", args.Password)
+	assert.Equal(t, "/other.sock", args.Socket)
+}
+
+func TestGenerateDSNFallsBackToOptionFile(t *testing.T) {
+	clearCredentialArguments(t)
+	path := filepath.Join(t.TempDir(), "my.cnf")
+	writeFile(t, path, "[monitoring]\nuser=monitor\npassword=secret\n")
+
+	args := arguments.ArgumentList{
+		Hostname:        "dbhost",
+		Port:            3306,
+		Username:        "root",
+		Password:        "password",
+		OptionFile:      path,
+		OptionFileGroup: "monitoring",
+	}
+	assert.Equal(t, "monitor:secret@tcp(dbhost:3306)/?", GenerateDSN(withOptionFiles(args), ""))
+}
+
+// clearCredentialArguments unsets the environment variables that make the username and password explicit.
+func clearCredentialArguments(t *testing.T) {
+	t.Helper()
+	t.Setenv("USERNAME", "")
+	t.Setenv("PASSWORD", "")
+}
+
+func TestApplyOptionsKeepsExplicitDefaults(t *testing.T) {
+	clearCredentialArguments(t)
+	t.Setenv("PASSWORD", "password")
+
+	args := applyOptions(arguments.ArgumentList{Username: "root", Password: "password"}, map[string]string{"user": "monitor", "password": "secret"})
+	assert.Equal(t, "monitor", args.Username)
+	assert.Equal(t, "password", args.Password)
+}
+
+func TestApplyOptionsIgnoresOperatingSystemUsername(t *testing.T) {
+	// USERNAME is set by Windows for the logged in user, it does not make the default username explicit
+	clearCredentialArguments(t)
+	t.Setenv("USERNAME", "root")
+
+	args := applyOptions(arguments.ArgumentList{Username: "root", Password: "password"}, map[string]string{"user": "monitor"})
+	assert.Equal(t, "monitor", args.Username)
+}
+
+func TestApplyOptionsIgnoresSocketOfRemoteHost(t *testing.T) {
+	options := map[string]string{"socket": "/var/run/mysqld/mysqld.sock"}
+
+	args := applyOptions(arguments.ArgumentList{Hostname: "db.example.com", Port: 3306}, options)
+	assert.Empty(t, args.Socket)
+
+	args = applyOptions(arguments.ArgumentList{Hostname: "localhost", Port: 3306}, options)
+	assert.Equal(t, "/var/run/mysqld/mysqld.sock", args.Socket)
+}
+
+// encryptLoginPathFile obfuscates content the same way mysql_config_editor does.
+func encryptLoginPathFile(t *testing.T, content string) []byte {
+	t.Helper()
+
+	rawKey := []byte("0123456789abcdefghij")
+	key := make([]byte, aes.BlockSize)
+	for i, b := range rawKey {
+		key[i%aes.BlockSize] ^= b
+	}
+	block, err := aes.NewCipher(key)
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	out.Write([]byte{0, 0, 0, 0})
+	out.Write(rawKey)
+	for _, line := range bytes.SplitAfter([]byte(content), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		padding := aes.BlockSize - len(line)%aes.BlockSize
+		line = append(line, bytes.Repeat([]byte{byte(padding)}, padding)...)
+		encrypted := make([]byte, len(line))
+		for i := 0; i < len(line); i += aes.BlockSize {
+			block.Encrypt(encrypted[i:i+aes.BlockSize], line[i:i+aes.BlockSize])
+		}
+		require.NoError(t, binary.Write(&out, binary.LittleEndian, uint32(len(encrypted))))
+		out.Write(encrypted)
+	}
+	return out.Bytes()
+}
+
+func TestReadLoginPathFile(t *testing.T) {
+	clearCredentialArguments(t)
+	path := filepath.Join(t.TempDir(), ".mylogin.cnf")
+	require.NoError(t, os.WriteFile(path, encryptLoginPathFile(t, "[client]\nuser = \"root\"\n[monitoring]\nuser = \"monitor\"\npassword = \"login-secret\"\n"), 0o600))
+
+	groups, err := readLoginPathFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "monitor", groups["monitoring"]["user"])
+	assert.Equal(t, "login-secret", groups["monitoring"]["password"])
+
+	t.Setenv("MYSQL_TEST_LOGIN_FILE", path)
+	args := withOptionFiles(arguments.ArgumentList{Username: "root", Password: "password", LoginPath: "monitoring"})
+	assert.Equal(t, "monitor", args.Username)
+	assert.Equal(t, "login-secret", args.Password)
+}
+
+func TestLoginPathTakesPrecedenceOverOptionFile(t *testing.T) {
+	clearCredentialArguments(t)
+	dir := t.TempDir()
+	loginPath := filepath.Join(dir, ".mylogin.cnf")
+	require.NoError(t, os.WriteFile(loginPath, encryptLoginPathFile(t, "[monitoring]\nuser = \"login-user\"\npassword = \"login-secret\"\n"), 0o600))
+	t.Setenv("MYSQL_TEST_LOGIN_FILE", loginPath)
+	optionFile := filepath.Join(dir, "my.cnf")
+	writeFile(t, optionFile, "[client]\nuser=file-user\npassword=file-secret\nssl-ca=/etc/mysql/ca.pem\n")
+
+	args := withOptionFiles(arguments.ArgumentList{
+		Username:        "root",
+		Password:        "password",
+		LoginPath:       "monitoring",
+		OptionFile:      optionFile,
+		OptionFileGroup: "client",
+	})
+	assert.Equal(t, "login-user", args.Username)
+	assert.Equal(t, "login-secret", args.Password)
+	assert.Equal(t, "/etc/mysql/ca.pem", args.TLSCaFile)
+	assert.Empty(t, args.LoginPath)
+	assert.Empty(t, args.OptionFile)
+}
+
+func TestLoginPathMergesClientGroup(t *testing.T) {
+	clearCredentialArguments(t)
+	path := filepath.Join(t.TempDir(), ".mylogin.cnf")
+	require.NoError(t, os.WriteFile(path, encryptLoginPathFile(t,
+		"[client]\nuser = \"client-user\"\npassword = \"client-secret\"\n[monitoring]\nuser = \"monitor\"\n"), 0o600))
+	t.Setenv("MYSQL_TEST_LOGIN_FILE", path)
+
+	args := withOptionFiles(arguments.ArgumentList{Username: "root", Password: "password", LoginPath: "monitoring"})
+	assert.Equal(t, "monitor", args.Username)
+	assert.Equal(t, "client-secret", args.Password)
+}
+
+func TestDecryptLoginPathFileInvalid(t *testing.T) {
+	_, err := decryptLoginPathFile([]byte("short"))
+	assert.ErrorIs(t, err, errInvalidLoginFile)
+}
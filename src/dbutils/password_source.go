@@ -0,0 +1,205 @@
+package dbutils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+// passwordSourceTimeout bounds the time spent running a password command or querying a secret store.
+const passwordSourceTimeout = 10 * time.Second
+
+var (
+	errVaultStatus        = errors.New("unexpected status from secret store")
+	errVaultFieldNotFound = errors.New("field not found in secret")
+)
+
+// PasswordSource resolves the password used to connect to the MySQL server.
+type PasswordSource interface {
+	// Name identifies the source, without revealing the secret, for caching and logging purposes.
+	Name() string
+	Password() (string, error)
+}
+
+// staticPasswordSource returns the password set in the configuration.
+type staticPasswordSource struct {
+	password string
+}
+
+// filePasswordSource reads the password from a file, such as a mounted Kubernetes secret.
+type filePasswordSource struct {
+	path string
+}
+
+// commandPasswordSource runs a command and uses its standard output as the password.
+type commandPasswordSource struct {
+	command string
+}
+
+// vaultPasswordSource reads the password from a Vault compatible HTTP secret endpoint.
+type vaultPasswordSource struct {
+	url    string
+	token  string
+	field  string
+	client *http.Client
+}
+
+// resolvedPasswords caches the password of every source, or its failure, so that all the connections opened
+// during a run share it and a failing command or secret store is not queried again.
+var resolvedPasswords sync.Map
+
+// resolvedPassword is the outcome of resolving the password of a source.
+type resolvedPassword struct {
+	password string
+	err      error
+}
+
+// NewPasswordSource returns the password source configured in the arguments. IAM authentication takes precedence
+// over a password file, a password command, a Vault endpoint and the plain password, in that order.
+func NewPasswordSource(args arguments.ArgumentList) PasswordSource {
+	switch {
+	case args.EnableIAMAuth:
+		return newIAMTokenSource(args)
+	case args.PasswordFile != "":
+		return filePasswordSource{path: args.PasswordFile}
+	case args.PasswordCommand != "":
+		return commandPasswordSource{command: args.PasswordCommand}
+	case args.PasswordVaultURL != "":
+		token := args.PasswordVaultToken
+		if token == "" {
+			token = os.Getenv("VAULT_TOKEN")
+		}
+		return vaultPasswordSource{
+			url:    args.PasswordVaultURL,
+			token:  token,
+			field:  args.PasswordVaultField,
+			client: &http.Client{Timeout: passwordSourceTimeout},
+		}
+	default:
+		return staticPasswordSource{password: args.Password}
+	}
+}
+
+// resolvePassword returns the password of the configured source, resolving it only once per run.
+func resolvePassword(args arguments.ArgumentList) (string, error) {
+	source := NewPasswordSource(args)
+	if static, ok := source.(staticPasswordSource); ok {
+		return static.password, nil
+	}
+	if cached, ok := resolvedPasswords.Load(source.Name()); ok {
+		resolved := cached.(resolvedPassword)
+		return resolved.password, resolved.err
+	}
+
+	password, err := source.Password()
+	if err != nil {
+		err = fmt.Errorf("error resolving password from %s: %w", source.Name(), err)
+	}
+	resolvedPasswords.Store(source.Name(), resolvedPassword{password: password, err: err})
+	return password, err
+}
+
+func (s staticPasswordSource) Name() string {
+	return "password argument"
+}
+
+func (s staticPasswordSource) Password() (string, error) {
+	return s.password, nil
+}
+
+func (s filePasswordSource) Name() string {
+	return "file " + s.path
+}
+
+func (s filePasswordSource) Password() (string, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+func (s commandPasswordSource) Name() string {
+	return "command " + s.command
+}
+
+func (s commandPasswordSource) Password() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), passwordSourceTimeout)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/C", s.command)
+	} else {
+		cmd = exec.CommandContext(ctx, "/bin/sh", "-c", s.command)
+	}
+	cmd.Stderr = os.Stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("error running password command: %w", err)
+	}
+	return strings.TrimRight(string(output), "\r\n"), nil
+}
+
+func (s vaultPasswordSource) Name() string {
+	return "vault " + s.url
+}
+
+// Password reads the configured field from a KV secret, supporting both the version 1 (`data.<field>`)
+// and version 2 (`data.data.<field>`) response layouts.
+func (s vaultPasswordSource) Password() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), passwordSourceTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return "", err
+	}
+	if s.token != "" {
+		req.Header.Set("X-Vault-Token", s.token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%w: %s", errVaultStatus, resp.Status)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var secret struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &secret); err != nil {
+		return "", fmt.Errorf("error decoding secret: %w", err)
+	}
+
+	data := secret.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+	password, ok := data[s.field].(string)
+	if !ok {
+		return "", fmt.Errorf("%w: %s", errVaultFieldNotFound, s.field)
+	}
+	return password, nil
+}
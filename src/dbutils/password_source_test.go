@@ -0,0 +1,128 @@
+package dbutils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPasswordSourcePrecedence(t *testing.T) {
+	assert.IsType(t, staticPasswordSource{}, NewPasswordSource(arguments.ArgumentList{Password: "secret"}))
+	assert.IsType(t, vaultPasswordSource{}, NewPasswordSource(arguments.ArgumentList{PasswordVaultURL: "http://vault"}))
+	assert.IsType(t, commandPasswordSource{}, NewPasswordSource(arguments.ArgumentList{PasswordCommand: "echo", PasswordVaultURL: "http://vault"}))
+	assert.IsType(t, filePasswordSource{}, NewPasswordSource(arguments.ArgumentList{PasswordFile: "/secret", PasswordCommand: "echo"}))
+}
+
+func TestFilePasswordSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(path, []byte("from-file\n"), 0o600))
+
+	password, err := filePasswordSource{path: path}.Password()
+	require.NoError(t, err)
+	assert.Equal(t, "from-file", password)
+
+	_, err = filePasswordSource{path: filepath.Join(t.TempDir(), "missing")}.Password()
+	assert.Error(t, err)
+}
+
+func TestCommandPasswordSource(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("command uses a POSIX shell")
+	}
+
+	password, err := commandPasswordSource{command: "printf 'from-command\\n'"}.Password()
+	require.NoError(t, err)
+	assert.Equal(t, "from-command", password)
+
+	_, err = commandPasswordSource{command: "exit 1"}.Password()
+	assert.Error(t, err)
+}
+
+func TestVaultPasswordSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "test-token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/mysql":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"kv-v2"},"metadata":{"version":3}}}`))
+		case "/v1/kv/mysql":
+			_, _ = w.Write([]byte(`{"data":{"db_password":"kv-v1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name     string
+		path     string
+		token    string
+		field    string
+		expected string
+		err      error
+	}{
+		{"KVVersion2", "/v1/secret/data/mysql", "test-token", "password", "kv-v2", nil},
+		{"KVVersion1", "/v1/kv/mysql", "test-token", "db_password", "kv-v1", nil},
+		{"MissingField", "/v1/kv/mysql", "test-token", "password", "", errVaultFieldNotFound},
+		{"Forbidden", "/v1/secret/data/mysql", "wrong-token", "password", "", errVaultStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := NewPasswordSource(arguments.ArgumentList{
+				PasswordVaultURL:   server.URL + tt.path,
+				PasswordVaultToken: tt.token,
+				PasswordVaultField: tt.field,
+			})
+			password, err := source.Password()
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, password)
+		})
+	}
+}
+
+func TestGenerateDSNResolvesPasswordOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "password")
+	require.NoError(t, os.WriteFile(path, []byte("first"), 0o600))
+
+	args := arguments.ArgumentList{Hostname: "dbhost", Port: 3306, Username: "dbuser", Password: "password", PasswordFile: path}
+	assert.Equal(t, "dbuser:first@tcp(dbhost:3306)/?", GenerateDSN(args, ""))
+
+	// Every connection opened during the same run uses the password resolved first
+	require.NoError(t, os.WriteFile(path, []byte("rotated"), 0o600))
+	assert.Equal(t, "dbuser:first@tcp(dbhost:3306)/app?", GenerateDSN(args, "app"))
+}
+
+func TestResolvePasswordFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	args := arguments.ArgumentList{Password: "password", PasswordFile: path}
+
+	password, err := resolvePassword(args)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+	assert.Empty(t, password)
+
+	// The failure is remembered for the rest of the run
+	require.NoError(t, os.WriteFile(path, []byte("late"), 0o600))
+	_, err = resolvePassword(args)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewConnectionManagerFailsWithPasswordSource(t *testing.T) {
+	args := arguments.ArgumentList{Hostname: "dbhost", Port: 3306, PasswordCommand: "exit 1"}
+
+	_, err := NewConnectionManager(args)
+	assert.ErrorContains(t, err, "error resolving password from command exit 1")
+}
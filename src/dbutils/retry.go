@@ -0,0 +1,110 @@
+package dbutils
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+// maxBackoffFactor caps the exponential backoff to this multiple of the initial backoff.
+const maxBackoffFactor = 16
+
+// retryableErrorNumbers lists the server and client error codes of transient connection failures.
+var retryableErrorNumbers = map[uint16]struct{}{
+	1040: {}, // ER_CON_COUNT_ERROR, too many connections
+	1053: {}, // ER_SERVER_SHUTDOWN
+	2006: {}, // CR_SERVER_GONE_ERROR
+	2013: {}, // CR_SERVER_LOST
+}
+
+// Retrier retries operations failing with transient connection errors using exponential backoff,
+// and counts the retries performed.
+type Retrier struct {
+	maxRetries int
+	backoff    time.Duration
+	deadline   time.Duration
+	retries    atomic.Int64
+	sleep      func(context.Context, time.Duration) error
+}
+
+// NewRetrier returns a Retrier configured with the ConnectRetries, ConnectRetryBackoff and ConnectRetryDeadline arguments.
+func NewRetrier(args arguments.ArgumentList) *Retrier {
+	return &Retrier{
+		maxRetries: args.ConnectRetries,
+		backoff:    time.Duration(args.ConnectRetryBackoff) * time.Millisecond,
+		deadline:   time.Duration(args.ConnectRetryDeadline) * time.Second,
+		sleep:      sleepContext,
+	}
+}
+
+// Do runs fn until it succeeds, fails with an error that is not transient, or the retries or the deadline are exhausted.
+// Authentication and TLS failures are never retried. A nil Retrier runs fn only once.
+func (r *Retrier) Do(ctx context.Context, fn func() error) error {
+	if r == nil {
+		return fn()
+	}
+
+	start := time.Now()
+	backoff := r.backoff
+
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || !IsRetryable(err) || attempt >= r.maxRetries {
+			return err
+		}
+		if r.deadline > 0 && time.Since(start)+backoff > r.deadline {
+			log.Debug("Retry deadline of %v exceeded: %v", r.deadline, err)
+			return err
+		}
+
+		log.Debug("Transient error, retrying in %v (%d/%d): %v", backoff, attempt+1, r.maxRetries, err)
+		if sleepErr := r.sleep(ctx, backoff); sleepErr != nil {
+			return err
+		}
+		r.retries.Add(1)
+		backoff = min(backoff*2, r.backoff*maxBackoffFactor)
+	}
+}
+
+// Retries returns the number of retries performed so far.
+func (r *Retrier) Retries() int {
+	if r == nil {
+		return 0
+	}
+	return int(r.retries.Load())
+}
+
+// IsRetryable reports whether err is a transient connection failure worth retrying.
+func IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		_, ok := retryableErrorNumbers[mysqlErr.Number]
+		return ok
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	switch ClassifyError(err) {
+	case ErrorClassNetwork, ErrorClassTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
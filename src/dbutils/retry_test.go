@@ -0,0 +1,130 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestRetrier returns a Retrier recording the backoffs instead of sleeping.
+func newTestRetrier(args arguments.ArgumentList, backoffs *[]time.Duration) *Retrier {
+	retrier := NewRetrier(args)
+	retrier.sleep = func(_ context.Context, d time.Duration) error {
+		*backoffs = append(*backoffs, d)
+		return nil
+	}
+	return retrier
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err       error
+		retryable bool
+	}{
+		{driver.ErrBadConn, true},
+		{mysql.ErrInvalidConn, true},
+		{&mysql.MySQLError{Number: 1040, Message: "Too many connections"}, true},
+		{&mysql.MySQLError{Number: 2013, Message: "Lost connection to MySQL server during query"}, true},
+		{fmt.Errorf("ping: %w", timeoutError{}), true},
+		{&mysql.MySQLError{Number: 1045, Message: "Access denied"}, false},
+		{mysql.ErrNativePassword, false},
+		{mysql.ErrNoTLS, false},
+		{&mysql.MySQLError{Number: 1146, Message: "Table doesn't exist"}, false},
+		{context.Canceled, false},
+		{errors.New("unexpected"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			assert.Equal(t, tt.retryable, IsRetryable(tt.err))
+		})
+	}
+}
+
+func TestRetrierRetriesTransientErrors(t *testing.T) {
+	var backoffs []time.Duration
+	retrier := newTestRetrier(arguments.ArgumentList{ConnectRetries: 5, ConnectRetryBackoff: 100, ConnectRetryDeadline: 30}, &backoffs)
+
+	attempts := 0
+	err := retrier.Do(context.Background(), func() error {
+		attempts++
+		if attempts < 4 {
+			return driver.ErrBadConn
+		}
+		return nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 4, attempts)
+	assert.Equal(t, 3, retrier.Retries())
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond}, backoffs)
+}
+
+func TestRetrierStopsAfterMaxRetries(t *testing.T) {
+	var backoffs []time.Duration
+	retrier := newTestRetrier(arguments.ArgumentList{ConnectRetries: 2, ConnectRetryBackoff: 10, ConnectRetryDeadline: 30}, &backoffs)
+
+	attempts := 0
+	err := retrier.Do(context.Background(), func() error {
+		attempts++
+		return mysql.ErrInvalidConn
+	})
+
+	assert.ErrorIs(t, err, mysql.ErrInvalidConn)
+	assert.Equal(t, 3, attempts)
+	assert.Equal(t, 2, retrier.Retries())
+}
+
+func TestRetrierNeverRetriesAuthErrors(t *testing.T) {
+	var backoffs []time.Duration
+	retrier := newTestRetrier(arguments.ArgumentList{ConnectRetries: 3, ConnectRetryBackoff: 10, ConnectRetryDeadline: 30}, &backoffs)
+	authErr := &mysql.MySQLError{Number: 1045, Message: "Access denied"}
+
+	attempts := 0
+	err := retrier.Do(context.Background(), func() error {
+		attempts++
+		return authErr
+	})
+
+	assert.ErrorIs(t, err, authErr)
+	assert.Equal(t, 1, attempts)
+	assert.Zero(t, retrier.Retries())
+	assert.Empty(t, backoffs)
+}
+
+func TestRetrierStopsAtDeadline(t *testing.T) {
+	var backoffs []time.Duration
+	// The first backoff of 2s already exceeds the deadline of 1s
+	retrier := newTestRetrier(arguments.ArgumentList{ConnectRetries: 3, ConnectRetryBackoff: 2000, ConnectRetryDeadline: 1}, &backoffs)
+
+	attempts := 0
+	err := retrier.Do(context.Background(), func() error {
+		attempts++
+		return driver.ErrBadConn
+	})
+
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Equal(t, 1, attempts)
+	assert.Zero(t, retrier.Retries())
+}
+
+func TestNilRetrierRunsOnce(t *testing.T) {
+	var retrier *Retrier
+
+	attempts := 0
+	err := retrier.Do(context.Background(), func() error {
+		attempts++
+		return driver.ErrBadConn
+	})
+
+	assert.ErrorIs(t, err, driver.ErrBadConn)
+	assert.Equal(t, 1, attempts)
+	assert.Zero(t, retrier.Retries())
+}
@@ -0,0 +1,39 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+)
+
+// SwitchSchema makes schema the default database of a pinned connection with `USE`, so that queries using
+// unqualified table names can run against it. The returned func restores the previous default database and must
+// be called before releasing the connection back to the pool.
+func SwitchSchema(ctx context.Context, conn *sql.Conn, schema string) (func(), error) {
+	var previous sql.NullString
+	if err := conn.QueryRowContext(ctx, "SELECT DATABASE()").Scan(&previous); err != nil {
+		return nil, fmt.Errorf("error reading default database: %w", err)
+	}
+	if _, err := conn.ExecContext(ctx, useStatement(schema)); err != nil {
+		return nil, fmt.Errorf("error switching to database %s: %w", schema, err)
+	}
+
+	return func() {
+		// A connection without default database cannot be reset to none, which is harmless as the
+		// collectors use schema qualified queries.
+		if !previous.Valid {
+			return
+		}
+		if _, err := conn.ExecContext(context.Background(), useStatement(previous.String)); err != nil {
+			log.Warn("Error restoring default database %s: %v", previous.String, err)
+		}
+	}, nil
+}
+
+// useStatement returns a `USE` statement with the schema name quoted as an identifier.
+func useStatement(schema string) string {
+	return "USE `" + strings.ReplaceAll(schema, "`", "``") + "`"
+}
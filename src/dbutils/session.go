@@ -0,0 +1,121 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+// errUnknownSystemVariable is the server error returned when setting a variable the server does not have.
+const errUnknownSystemVariable = 1193
+
+// sessionSetting is a session variable applied to every new connection. Alternatives are tried in order until
+// the server accepts one, as variable names differ between MySQL versions and MariaDB.
+type sessionSetting struct {
+	name         string
+	alternatives []string
+}
+
+// sessionConnector applies the monitoring session profile to every connection it opens, so that monitoring
+// queries are bounded in time, never wait on locks for long, cannot write and are not logged as slow queries.
+type sessionConnector struct {
+	driver.Connector
+	settings []sessionSetting
+}
+
+func newSessionConnector(connector driver.Connector, args arguments.ArgumentList) *sessionConnector {
+	return &sessionConnector{
+		Connector: connector,
+		settings:  sessionSettings(args),
+	}
+}
+
+func (c *sessionConnector) Connect(ctx context.Context) (driver.Conn, error) {
+	conn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	applySessionSettings(ctx, conn, c.settings)
+	return conn, nil
+}
+
+// sessionSettings returns the session profile configured in the arguments. Settings with a zero value are not applied.
+func sessionSettings(args arguments.ArgumentList) []sessionSetting {
+	var settings []sessionSetting
+	if args.SessionMaxExecutionTime > 0 {
+		settings = append(settings, sessionSetting{
+			name: "max_execution_time",
+			alternatives: []string{
+				fmt.Sprintf("SET SESSION max_execution_time = %d", args.SessionMaxExecutionTime),
+				// MariaDB equivalent, in seconds
+				"SET SESSION max_statement_time = " + strconv.FormatFloat(float64(args.SessionMaxExecutionTime)/1000, 'f', -1, 64),
+			},
+		})
+	}
+	if args.SessionLockWaitTimeout > 0 {
+		settings = append(settings, sessionSetting{
+			name:         "lock_wait_timeout",
+			alternatives: []string{fmt.Sprintf("SET SESSION lock_wait_timeout = %d", args.SessionLockWaitTimeout)},
+		})
+	}
+	if args.SessionInnodbLockWaitTimeout > 0 {
+		settings = append(settings, sessionSetting{
+			name:         "innodb_lock_wait_timeout",
+			alternatives: []string{fmt.Sprintf("SET SESSION innodb_lock_wait_timeout = %d", args.SessionInnodbLockWaitTimeout)},
+		})
+	}
+	if args.SessionReadOnly {
+		settings = append(settings, sessionSetting{
+			name: "transaction_read_only",
+			alternatives: []string{
+				"SET SESSION transaction_read_only = ON",
+				// MySQL before 5.7.20 and MariaDB before 11.1
+				"SET SESSION tx_read_only = ON",
+			},
+		})
+	}
+	if args.SessionLongQueryTime > 0 {
+		settings = append(settings, sessionSetting{
+			name:         "long_query_time",
+			alternatives: []string{fmt.Sprintf("SET SESSION long_query_time = %d", args.SessionLongQueryTime)},
+		})
+	}
+	return settings
+}
+
+// applySessionSettings runs the settings on a new connection. A setting the server rejects is skipped,
+// as a partially guarded session is preferable to not monitoring the server at all.
+func applySessionSettings(ctx context.Context, conn driver.Conn, settings []sessionSetting) {
+	execer, ok := conn.(driver.ExecerContext)
+	if !ok {
+		log.Warn("Connection does not support executing statements, session settings not applied")
+		return
+	}
+
+	for _, setting := range settings {
+		applied := false
+		for _, statement := range setting.alternatives {
+			_, err := execer.ExecContext(ctx, statement, nil)
+			if err == nil {
+				log.Debug("Session setting applied: %s", statement)
+				applied = true
+				break
+			}
+
+			var mysqlErr *mysql.MySQLError
+			if !errors.As(err, &mysqlErr) || mysqlErr.Number != errUnknownSystemVariable {
+				log.Debug("Could not apply session setting %s: %v", statement, err)
+				break
+			}
+		}
+		if !applied {
+			log.Debug("Session setting %s is not applied", setting.name)
+		}
+	}
+}
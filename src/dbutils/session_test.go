@@ -0,0 +1,104 @@
+package dbutils
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingConn is a driver connection recording the executed statements. Statements on the unknown
+// variables fail as they would on a server lacking them.
+type recordingConn struct {
+	driver.Conn
+	unknown    []string
+	statements []string
+}
+
+func (c *recordingConn) ExecContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Result, error) {
+	for _, name := range c.unknown {
+		if strings.Contains(query, " "+name+" ") {
+			return nil, &mysql.MySQLError{Number: errUnknownSystemVariable, Message: "Unknown system variable '" + name + "'"}
+		}
+	}
+	if strings.Contains(query, "long_query_time") {
+		return nil, errors.New("connection lost")
+	}
+	c.statements = append(c.statements, query)
+	return driver.RowsAffected(0), nil
+}
+
+type recordingConnector struct {
+	driver.Connector
+	conn *recordingConn
+}
+
+func (c recordingConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func defaultSessionArgs() arguments.ArgumentList {
+	return arguments.ArgumentList{
+		SessionMaxExecutionTime:      1500,
+		SessionLockWaitTimeout:       5,
+		SessionInnodbLockWaitTimeout: 3,
+		SessionReadOnly:              true,
+		SessionLongQueryTime:         3600,
+	}
+}
+
+func TestSessionSettings(t *testing.T) {
+	settings := sessionSettings(defaultSessionArgs())
+	require.Len(t, settings, 5)
+	assert.Equal(t, []string{"SET SESSION max_execution_time = 1500", "SET SESSION max_statement_time = 1.5"}, settings[0].alternatives)
+	assert.Equal(t, []string{"SET SESSION transaction_read_only = ON", "SET SESSION tx_read_only = ON"}, settings[3].alternatives)
+
+	assert.Empty(t, sessionSettings(arguments.ArgumentList{}))
+}
+
+func TestSessionConnectorAppliesSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		unknown  []string
+		expected []string
+	}{
+		{
+			name: "MySQL",
+			expected: []string{
+				"SET SESSION max_execution_time = 1500",
+				"SET SESSION lock_wait_timeout = 5",
+				"SET SESSION innodb_lock_wait_timeout = 3",
+				"SET SESSION transaction_read_only = ON",
+			},
+		},
+		{
+			name:    "MariaDB",
+			unknown: []string{"max_execution_time", "transaction_read_only"},
+			expected: []string{
+				"SET SESSION max_statement_time = 1.5",
+				"SET SESSION lock_wait_timeout = 5",
+				"SET SESSION innodb_lock_wait_timeout = 3",
+				"SET SESSION tx_read_only = ON",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := &recordingConn{unknown: tt.unknown}
+			connector := newSessionConnector(recordingConnector{conn: conn}, defaultSessionArgs())
+
+			got, err := connector.Connect(context.Background())
+			require.NoError(t, err)
+			assert.Same(t, conn, got)
+			// The failing long_query_time setting does not prevent the connection from being used
+			assert.Equal(t, tt.expected, conn.statements)
+		})
+	}
+}
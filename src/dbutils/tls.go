@@ -0,0 +1,109 @@
+package dbutils
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/go-sql-driver/mysql"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+var (
+	errInvalidCaFile        = errors.New("no valid PEM certificates found in CA file")
+	errIncompleteClientCert = errors.New("both TLS client certificate and key files must be provided")
+	errUnsupportedTLS       = errors.New("unsupported TLS version")
+)
+
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// hasCustomTLS reports whether the arguments require a TLS configuration other than the driver presets.
+func hasCustomTLS(args arguments.ArgumentList) bool {
+	return args.TLSCaFile != "" ||
+		args.TLSCertFile != "" ||
+		args.TLSKeyFile != "" ||
+		args.TLSServerName != "" ||
+		args.TLSMinVersion != ""
+}
+
+// TLSConfigName returns the name the custom TLS configuration of a target is registered with in the driver.
+func TLSConfigName(args arguments.ArgumentList) string {
+	if args.Socket != "" {
+		return "nri-mysql-" + args.Socket
+	}
+	return fmt.Sprintf("nri-mysql-%s-%d", args.Hostname, args.Port)
+}
+
+// RegisterTLSConfig builds the TLS configuration described by the arguments and registers it in the driver,
+// so that every connection generated by GenerateDSN for the same target shares it. It is a no-op when no
+// custom TLS argument is set.
+func RegisterTLSConfig(args arguments.ArgumentList) error {
+	if !hasCustomTLS(args) {
+		return nil
+	}
+
+	config, err := newTLSConfig(args)
+	if err != nil {
+		return fmt.Errorf("error building TLS configuration: %w", err)
+	}
+	return mysql.RegisterTLSConfig(TLSConfigName(args), config)
+}
+
+func newTLSConfig(args arguments.ArgumentList) (*tls.Config, error) {
+	minVersion, err := parseTLSVersion(args.TLSMinVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	config := &tls.Config{
+		MinVersion:         minVersion,
+		ServerName:         args.TLSServerName,
+		InsecureSkipVerify: args.InsecureSkipVerify, // nolint: gosec
+	}
+
+	if args.TLSCaFile != "" {
+		caData, err := os.ReadFile(args.TLSCaFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("%w: %s", errInvalidCaFile, args.TLSCaFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if args.TLSCertFile != "" || args.TLSKeyFile != "" {
+		if args.TLSCertFile == "" || args.TLSKeyFile == "" {
+			return nil, errIncompleteClientCert
+		}
+		certificate, err := tls.LoadX509KeyPair(args.TLSCertFile, args.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error loading client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+
+	return config, nil
+}
+
+// parseTLSVersion converts a version such as "1.2" into its crypto/tls constant, defaulting to TLS 1.2.
+// FIPS builds never negotiate versions below TLS 1.2, as crypto/tls/fipsonly is imported with the fips build tag.
+func parseTLSVersion(version string) (uint16, error) {
+	if version == "" {
+		return tls.VersionTLS12, nil
+	}
+
+	tlsVersion, ok := tlsVersions[version]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s", errUnsupportedTLS, version)
+	}
+	return tlsVersion, nil
+}
@@ -0,0 +1,114 @@
+package dbutils
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key to dir, returning both paths.
+func writeTestCertificate(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "nri-mysql-test"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPath := filepath.Join(dir, "cert.pem")
+	keyPath := filepath.Join(dir, "key.pem")
+	require.NoError(t, os.WriteFile(certPath, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(keyPath, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+	return certPath, keyPath
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	certPath, keyPath := writeTestCertificate(t, t.TempDir())
+
+	config, err := newTLSConfig(arguments.ArgumentList{
+		TLSCaFile:     certPath,
+		TLSCertFile:   certPath,
+		TLSKeyFile:    keyPath,
+		TLSServerName: "mysql.internal",
+		TLSMinVersion: "1.3",
+	})
+	require.NoError(t, err)
+
+	assert.NotNil(t, config.RootCAs)
+	assert.Len(t, config.Certificates, 1)
+	assert.Equal(t, "mysql.internal", config.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS13), config.MinVersion)
+	assert.False(t, config.InsecureSkipVerify)
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	certPath, _ := writeTestCertificate(t, dir)
+	invalidCa := filepath.Join(dir, "invalid.pem")
+	require.NoError(t, os.WriteFile(invalidCa, []byte("not a certificate"), 0o600))
+
+	tests := []struct {
+		name string
+		args arguments.ArgumentList
+		err  error
+	}{
+		{"InvalidCa", arguments.ArgumentList{TLSCaFile: invalidCa}, errInvalidCaFile},
+		{"CertificateWithoutKey", arguments.ArgumentList{TLSCertFile: certPath}, errIncompleteClientCert},
+		{"UnsupportedVersion", arguments.ArgumentList{TLSMinVersion: "2.0"}, errUnsupportedTLS},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newTLSConfig(tt.args)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	version, err := parseTLSVersion("")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS12), version)
+
+	version, err = parseTLSVersion("1.1")
+	require.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS11), version)
+
+	_, err = parseTLSVersion("2.0")
+	assert.ErrorIs(t, err, errUnsupportedTLS)
+}
+
+func TestRegisterTLSConfig(t *testing.T) {
+	certPath, _ := writeTestCertificate(t, t.TempDir())
+
+	assert.NoError(t, RegisterTLSConfig(arguments.ArgumentList{Hostname: "dbhost", Port: 3306}))
+
+	args := arguments.ArgumentList{Hostname: "dbhost", Port: 1234, Username: "dbuser", Password: "dbpwd", TLSCaFile: certPath}
+	require.NoError(t, RegisterTLSConfig(args))
+	assert.Equal(t, "dbuser:dbpwd@tcp(dbhost:1234)/?tls=nri-mysql-dbhost-1234", GenerateDSN(args, ""))
+}
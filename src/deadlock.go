@@ -0,0 +1,232 @@
+package main
+
+import (
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+	utils "github.com/newrelic/nri-mysql/src/query-performance-monitoring/utils"
+)
+
+const (
+	deadlockSampleName  = "MysqlDeadlockSample"
+	deadlockSectionName = "LATEST DETECTED DEADLOCK"
+)
+
+var (
+	deadlockTransactionHeaderRegex = regexp.MustCompile(`^\*\*\* \((\d+)\) TRANSACTION:`)
+	deadlockWaitingRegex           = regexp.MustCompile(`^\*\*\* (\(\d+\) )?WAITING FOR THIS LOCK TO BE GRANTED:`)
+	deadlockHoldsRegex             = regexp.MustCompile(`^\*\*\* (\(\d+\) )?HOLDS THE LOCK\(S\):`)
+	deadlockRollbackRegex          = regexp.MustCompile(`^\*\*\* WE ROLL BACK TRANSACTION \((\d+)\)`)
+	deadlockTransactionRegex       = regexp.MustCompile(`^TRANSACTION (\S+?),? ACTIVE (\d+) sec`)
+	deadlockThreadRegex            = regexp.MustCompile(`^(?:MySQL|MariaDB) thread id (\d+), OS thread handle \S+, query id \d+ (.+)$`)
+	deadlockRecordLockRegex        = regexp.MustCompile(`^RECORD LOCKS .* index (\S+) of table (\S+) trx id \S+ lock[_ ]mode (.+?)( waiting)?$`)
+	deadlockTableLockRegex         = regexp.MustCompile(`^TABLE LOCK table (\S+) trx id \S+ lock mode (.+?)( waiting)?$`)
+)
+
+// deadlockLock is a lock a deadlocked transaction holds or waits for.
+type deadlockLock struct {
+	table string
+	index string
+	mode  string
+}
+
+// deadlockTransaction is one of the transactions involved in a deadlock.
+type deadlockTransaction struct {
+	number        string
+	id            string
+	activeSeconds int
+	threadID      string
+	host          string
+	user          string
+	query         string
+	waiting       deadlockLock
+	held          deadlockLock
+}
+
+// deadlock is the latest deadlock detected by InnoDB. The header holds the time it was detected and the thread
+// detecting it, identifying the deadlock between runs.
+type deadlock struct {
+	header       string
+	detectedAt   string
+	rolledBack   string
+	transactions []*deadlockTransaction
+}
+
+// populateDeadlock reports the latest deadlock found in the InnoDB status as MysqlDeadlockSample of the node
+// entity. The last reported deadlock is kept in the state store so that every deadlock is reported only once.
+func populateDeadlock(e *integration.Entity, status string, args arguments.ArgumentList, state persist.Storer) {
+	latest, ok := parseLatestDeadlock(status)
+	if !ok {
+		return
+	}
+
+	key := stateKey(args, "deadlock")
+	var reported string
+	_, err := state.Get(key, &reported)
+	// The key is refreshed on every run, otherwise it would expire and the same deadlock would be reported again
+	state.Set(key, latest.header)
+	if err == nil && reported == latest.header {
+		log.Debug("Deadlock detected at %s already reported", latest.detectedAt)
+		return
+	}
+
+	ms := infrautils.MetricSet(
+		e,
+		deadlockSampleName,
+		args.Hostname,
+		args.Port,
+		args.RemoteMonitoring,
+	)
+	setDeadlockMetric(ms, "deadlock.detectedAt", latest.detectedAt, metric.ATTRIBUTE)
+	setDeadlockMetric(ms, "deadlock.rolledBackTransaction", latest.rolledBack, metric.ATTRIBUTE)
+	setDeadlockMetric(ms, "deadlock.transactions", len(latest.transactions), metric.GAUGE)
+
+	for _, trx := range latest.transactions {
+		prefix := "transaction" + trx.number + "."
+		setDeadlockMetric(ms, prefix+"id", trx.id, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"activeSeconds", trx.activeSeconds, metric.GAUGE)
+		setDeadlockMetric(ms, prefix+"threadId", trx.threadID, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"user", trx.user, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"host", trx.host, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"query", trx.query, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"waitingTable", trx.waiting.table, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"waitingIndex", trx.waiting.index, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"waitingLockMode", trx.waiting.mode, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"heldTable", trx.held.table, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"heldIndex", trx.held.index, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"heldLockMode", trx.held.mode, metric.ATTRIBUTE)
+		setDeadlockMetric(ms, prefix+"rolledBack", strconv.FormatBool(trx.number == latest.rolledBack), metric.ATTRIBUTE)
+	}
+}
+
+// setDeadlockMetric sets a metric of the deadlock sample, skipping the values missing from the InnoDB status.
+func setDeadlockMetric(ms *metric.Set, name string, value interface{}, metricType metric.SourceType) {
+	if value == "" {
+		return
+	}
+	if err := ms.SetMetric(name, value, metricType); err != nil {
+		log.Warn("Error setting value: %s", err)
+	}
+}
+
+// parseLatestDeadlock parses the LATEST DETECTED DEADLOCK section of the InnoDB status, which is only present
+// once a deadlock has been detected since the server started. Queries are anonymized.
+func parseLatestDeadlock(status string) (*deadlock, bool) {
+	lines, ok := splitInnodbStatusSections(status)[deadlockSectionName]
+	if !ok {
+		return nil, false
+	}
+
+	latest := &deadlock{}
+	var trx *deadlockTransaction
+	var lock *deadlockLock
+	var query []string
+	inQuery := false
+
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "***") {
+			if inQuery {
+				trx.query = anonymizeDeadlockQuery(query)
+				inQuery = false
+			}
+			lock = nil
+		}
+
+		switch match := deadlockTransactionHeaderRegex.FindStringSubmatch(line); {
+		case match != nil:
+			trx = &deadlockTransaction{number: match[1]}
+			latest.transactions = append(latest.transactions, trx)
+			continue
+		case trx != nil && deadlockWaitingRegex.MatchString(line):
+			lock = &trx.waiting
+			continue
+		case trx != nil && deadlockHoldsRegex.MatchString(line):
+			lock = &trx.held
+			continue
+		}
+		if match := deadlockRollbackRegex.FindStringSubmatch(line); match != nil {
+			latest.rolledBack = match[1]
+			continue
+		}
+
+		switch {
+		case latest.header == "" && trx == nil && line != "":
+			latest.header = line
+			if fields := strings.Fields(line); len(fields) >= 2 {
+				latest.detectedAt = fields[0] + " " + fields[1]
+			}
+		case lock != nil && lock.table == "":
+			parseDeadlockLock(line, lock)
+		case inQuery && line != "":
+			query = append(query, line)
+		case trx != nil && !inQuery && trx.query == "":
+			parseDeadlockTransactionLine(line, trx, &inQuery)
+			if inQuery {
+				query = nil
+			}
+		}
+	}
+	if inQuery {
+		trx.query = anonymizeDeadlockQuery(query)
+	}
+
+	return latest, latest.header != "" && len(latest.transactions) > 0
+}
+
+// parseDeadlockTransactionLine parses the transaction id and the thread of a deadlocked transaction. The lines
+// following the thread hold the query.
+func parseDeadlockTransactionLine(line string, trx *deadlockTransaction, inQuery *bool) {
+	if match := deadlockTransactionRegex.FindStringSubmatch(line); match != nil {
+		trx.id = match[1]
+		trx.activeSeconds, _ = strconv.Atoi(match[2])
+	}
+	if match := deadlockThreadRegex.FindStringSubmatch(line); match != nil {
+		trx.threadID = match[1]
+		trx.host, trx.user = parseDeadlockThreadClient(strings.Fields(match[2]))
+		*inQuery = true
+	}
+}
+
+/*
+parseDeadlockThreadClient returns the client host and user of the fields following the query id of a thread line.
+InnoDB prints the host, the IP address when the host was resolved, the user and the thread state, so the user is
+the field following the IP address when there is one.
+*/
+func parseDeadlockThreadClient(fields []string) (string, string) {
+	switch {
+	case len(fields) < 2:
+		return "", ""
+	case len(fields) > 2 && net.ParseIP(fields[1]) != nil:
+		return fields[0], fields[2]
+	default:
+		return fields[0], fields[1]
+	}
+}
+
+func parseDeadlockLock(line string, lock *deadlockLock) {
+	if match := deadlockRecordLockRegex.FindStringSubmatch(line); match != nil {
+		lock.index = match[1]
+		lock.table = strings.ReplaceAll(match[2], "`", "")
+		lock.mode = match[3]
+		return
+	}
+	if match := deadlockTableLockRegex.FindStringSubmatch(line); match != nil {
+		lock.table = strings.ReplaceAll(match[1], "`", "")
+		lock.mode = match[2]
+	}
+}
+
+func anonymizeDeadlockQuery(lines []string) string {
+	query := strings.Join(lines, " ")
+	return *utils.AnonymizeQueryText(&query)
+}
@@ -0,0 +1,140 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func readInnodbStatus(t *testing.T, name string) string {
+	t.Helper()
+	status, err := os.ReadFile(filepath.Join("testdata", "innodb_status", name))
+	require.NoError(t, err)
+	return string(status)
+}
+
+func TestParseLatestDeadlockMySQL57(t *testing.T) {
+	latest, ok := parseLatestDeadlock(readInnodbStatus(t, "mysql57.txt"))
+	require.True(t, ok)
+
+	assert.Equal(t, "2026-09-14 10:15:42", latest.detectedAt)
+	assert.Equal(t, "2", latest.rolledBack)
+	require.Len(t, latest.transactions, 2)
+
+	first := latest.transactions[0]
+	assert.Equal(t, "2467901", first.id)
+	assert.Equal(t, 5, first.activeSeconds)
+	assert.Equal(t, "45", first.threadID)
+	assert.Equal(t, "10.0.0.11", first.host)
+	assert.Equal(t, "app", first.user)
+	assert.Equal(t, "UPDATE accounts SET balance = balance - ? WHERE id = ? AND owner = ?", first.query)
+	assert.Equal(t, deadlockLock{table: "bank.accounts", index: "PRIMARY", mode: "X locks rec but not gap"}, first.waiting)
+	assert.Empty(t, first.held.table)
+
+	second := latest.transactions[1]
+	assert.Equal(t, "UPDATE accounts SET balance = balance + ? WHERE id = ?", second.query)
+	assert.Equal(t, deadlockLock{table: "bank.accounts", index: "PRIMARY", mode: "X locks rec but not gap"}, second.held)
+	assert.Equal(t, "PRIMARY", second.waiting.index)
+}
+
+func TestParseLatestDeadlockMySQL80(t *testing.T) {
+	latest, ok := parseLatestDeadlock(readInnodbStatus(t, "mysql80.txt"))
+	require.True(t, ok)
+
+	assert.Equal(t, "1", latest.rolledBack)
+	require.Len(t, latest.transactions, 2)
+	assert.Equal(t, "SELECT * FROM inventory WHERE sku = ? FOR UPDATE", latest.transactions[0].query)
+	assert.Equal(t, "idx_sku", latest.transactions[0].held.index)
+	assert.Equal(t, "shop.inventory", latest.transactions[1].waiting.table)
+
+	assert.Equal(t, "172.17.0.1", latest.transactions[1].host)
+	assert.Equal(t, "app", latest.transactions[1].user)
+}
+
+func TestParseLatestDeadlockResolvedHost(t *testing.T) {
+	// Clients whose host name is resolved are printed as "host ip user"
+	status := strings.Replace(readInnodbStatus(t, "mysql80.txt"),
+		"query id 1841 172.17.0.1 app statistics", "query id 1841 web-2.shop.internal 172.17.0.5 app statistics", 1)
+	latest, ok := parseLatestDeadlock(status)
+	require.True(t, ok)
+
+	require.Len(t, latest.transactions, 2)
+	assert.Equal(t, "172.17.0.1", latest.transactions[0].host)
+	assert.Equal(t, "app", latest.transactions[0].user)
+	assert.Equal(t, "web-2.shop.internal", latest.transactions[1].host)
+	assert.Equal(t, "app", latest.transactions[1].user)
+}
+
+func TestParseLatestDeadlockMariaDB(t *testing.T) {
+	latest, ok := parseLatestDeadlock(readInnodbStatus(t, "mariadb106.txt"))
+	require.True(t, ok)
+
+	require.Len(t, latest.transactions, 2)
+	first := latest.transactions[0]
+	assert.Equal(t, "localhost", first.host)
+	assert.Equal(t, "root", first.user)
+	assert.Equal(t, "DELETE FROM sessions WHERE id = ?", first.query)
+	assert.Equal(t, "PRIMARY", first.waiting.index)
+	assert.Empty(t, first.held.table)
+	assert.Equal(t, deadlockLock{table: "app.sessions", mode: "IX"}, latest.transactions[1].waiting)
+}
+
+func TestParseLatestDeadlockMissing(t *testing.T) {
+	_, ok := parseLatestDeadlock("----------\nSEMAPHORES\n----------\nOS WAIT ARRAY INFO: reservation count 1\n")
+	assert.False(t, ok)
+}
+
+func TestPopulateDeadlockReportsOnce(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+	state := persist.NewInMemoryStore()
+	args := arguments.ArgumentList{Hostname: "localhost", Port: 3306}
+	status := readInnodbStatus(t, "mysql57.txt")
+
+	populateDeadlock(e, status, args, state)
+	require.Len(t, e.Metrics, 1)
+	sample := e.Metrics[0].Metrics
+	assert.Equal(t, deadlockSampleName, sample["event_type"])
+	assert.Equal(t, "2026-09-14 10:15:42", sample["deadlock.detectedAt"])
+	assert.Equal(t, "2", sample["deadlock.rolledBackTransaction"])
+	assert.Equal(t, "bank.accounts", sample["transaction1.waitingTable"])
+	assert.Equal(t, "true", sample["transaction2.rolledBack"])
+	assert.NotContains(t, sample, "transaction1.heldTable")
+
+	populateDeadlock(e, status, args, state)
+	assert.Len(t, e.Metrics, 1)
+
+	populateDeadlock(e, readInnodbStatus(t, "mysql80.txt"), args, state)
+	assert.Len(t, e.Metrics, 2)
+}
+
+func TestPopulateDeadlockReportsOnceAfterStateTTL(t *testing.T) {
+	start := time.Date(2026, 9, 14, 10, 0, 0, 0, time.UTC)
+	defer persist.SetNow(time.Now)
+
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+	state, err := persist.NewFileStore(filepath.Join(t.TempDir(), "state.json"), log.NewStdErr(false), stateTTL)
+	require.NoError(t, err)
+	args := arguments.ArgumentList{Hostname: "localhost", Port: 3306}
+	status := readInnodbStatus(t, "mysql57.txt")
+
+	// The same deadlock stays in the InnoDB status for longer than the state TTL
+	for _, elapsed := range []time.Duration{0, 20 * time.Hour, 30 * time.Hour, 31 * time.Hour} {
+		persist.SetNow(func() time.Time { return start.Add(elapsed) })
+		populateDeadlock(e, status, args, state)
+		require.NoError(t, state.Save())
+	}
+	assert.Len(t, e.Metrics, 1)
+}
@@ -9,7 +9,12 @@ var defaultMetricsBase = map[string][]interface{}{
 	"net.abortedConnectsPerSecond":                {"Aborted_connects", metric.PRATE},
 	"net.bytesReceivedPerSecond":                  {"Bytes_received", metric.PRATE},
 	"net.bytesSentPerSecond":                      {"Bytes_sent", metric.PRATE},
+	"net.connectionErrorsAcceptPerSecond":         {"Connection_errors_accept", metric.PRATE},
+	"net.connectionErrorsInternalPerSecond":       {"Connection_errors_internal", metric.PRATE},
 	"net.connectionErrorsMaxConnectionsPerSecond": {"Connection_errors_max_connections", metric.PRATE},
+	"net.connectionErrorsPeerAddressPerSecond":    {"Connection_errors_peer_address", metric.PRATE},
+	"net.connectionErrorsSelectPerSecond":         {"Connection_errors_select", metric.PRATE},
+	"net.connectionErrorsTcpwrapPerSecond":        {"Connection_errors_tcpwrap", metric.PRATE},
 	"net.connectionsPerSecond":                    {"Connections", metric.PRATE},
 	"net.maxUsedConnections":                      {"Max_used_connections", metric.GAUGE},
 	"net.threadsConnected":                        {"Threads_connected", metric.GAUGE},
@@ -107,5 +112,5 @@ func getDefaultMetrics(dbVersion string) map[string][]interface{} {
 	if isDBVersionLessThan8(dbVersion) {
 		return mergeMaps(defaultMetricsBase, defaultMetricsBelowVersion8)
 	}
-	return defaultMetricsBase
+	return mergeMaps(defaultMetricsBase, nil)
 }
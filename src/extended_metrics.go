@@ -58,5 +58,5 @@ func getExtendedMetrics(dbVersion string) map[string][]interface{} {
 	if isDBVersionLessThan8(dbVersion) {
 		return mergeMaps(extendedMetricsBase, extendedMetricsBelowVersion8)
 	}
-	return extendedMetricsBase
+	return mergeMaps(extendedMetricsBase, nil)
 }
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+)
+
+// galeraMetrics are reported in MysqlSample by the nodes of a Galera, Percona XtraDB Cluster or MariaDB Galera
+// cluster, from the wsrep_* status variables.
+var galeraMetrics = map[string][]interface{}{
+	"cluster.galeraSize":                         {"wsrep_cluster_size", metric.GAUGE},
+	"cluster.galeraStatus":                       {"wsrep_cluster_status", metric.ATTRIBUTE},
+	"cluster.galeraStateUuid":                    {"wsrep_cluster_state_uuid", metric.ATTRIBUTE},
+	"cluster.galeraLocalState":                   {"wsrep_local_state", metric.GAUGE},
+	"cluster.galeraLocalStateComment":            {"wsrep_local_state_comment", metric.ATTRIBUTE},
+	"cluster.galeraState":                        {"galera_state", metric.ATTRIBUTE},
+	"cluster.galeraLocalIndex":                   {"wsrep_local_index", metric.GAUGE},
+	"cluster.galeraReady":                        {"wsrep_ready", metric.ATTRIBUTE},
+	"cluster.galeraConnected":                    {"wsrep_connected", metric.ATTRIBUTE},
+	"cluster.galeraFlowControlPaused":            {"wsrep_flow_control_paused", metric.GAUGE},
+	"cluster.galeraFlowControlPausedNsPerSecond": {"wsrep_flow_control_paused_ns", metric.PRATE},
+	"cluster.galeraFlowControlSentPerSecond":     {"wsrep_flow_control_sent", metric.PRATE},
+	"cluster.galeraFlowControlReceivedPerSecond": {"wsrep_flow_control_recv", metric.PRATE},
+	"cluster.galeraLocalRecvQueue":               {"wsrep_local_recv_queue", metric.GAUGE},
+	"cluster.galeraLocalRecvQueueAvg":            {"wsrep_local_recv_queue_avg", metric.GAUGE},
+	"cluster.galeraLocalSendQueue":               {"wsrep_local_send_queue", metric.GAUGE},
+	"cluster.galeraLocalSendQueueAvg":            {"wsrep_local_send_queue_avg", metric.GAUGE},
+	"cluster.galeraLocalCertFailuresPerSecond":   {"wsrep_local_cert_failures", metric.PRATE},
+	"cluster.galeraLocalBfAbortsPerSecond":       {"wsrep_local_bf_aborts", metric.PRATE},
+	"cluster.galeraCertDepsDistance":             {"wsrep_cert_deps_distance", metric.GAUGE},
+	"cluster.galeraReplicatedPerSecond":          {"wsrep_replicated", metric.PRATE},
+	"cluster.galeraReplicatedBytesPerSecond":     {"wsrep_replicated_bytes", metric.PRATE},
+	"cluster.galeraReceivedPerSecond":            {"wsrep_received", metric.PRATE},
+	"cluster.galeraReceivedBytesPerSecond":       {"wsrep_received_bytes", metric.PRATE},
+	"cluster.galeraReplicationLatencyAvgSeconds": {galeraReplicationLatencyAvg, metric.GAUGE},
+	"cluster.galeraReplicationLatencyMaxSeconds": {galeraReplicationLatencyMax, metric.GAUGE},
+}
+
+/*
+getGaleraData flags the raw metrics of Galera nodes, detected by wsrep_on=ON, and adds the local state of the node,
+such as synced or donor/desynced. The node type is left as detected from the replica status, so that nodes also
+replicating from an asynchronous source keep their replication metrics.
+*/
+func getGaleraData(inventory map[string]interface{}, metrics map[string]interface{}) {
+	if !isGaleraNode(inventory) {
+		return
+	}
+	metrics["wsrep_on"] = "ON"
+	if state, ok := metrics["wsrep_local_state_comment"]; ok && fmt.Sprint(state) != "" {
+		metrics["galera_state"] = strings.ToLower(fmt.Sprint(state))
+	}
+}
+
+func isGaleraNode(inventory map[string]interface{}) bool {
+	switch value := inventory["wsrep_on"].(type) {
+	case string:
+		return strings.EqualFold(value, "ON")
+	case bool:
+		return value
+	case int:
+		return value == 1
+	}
+	return false
+}
+
+// galeraReplicationLatencyAvg returns the average replication latency of wsrep_evs_repl_latency, which is
+// reported as min/avg/max/stddev/sample_size in seconds.
+func galeraReplicationLatencyAvg(metrics map[string]interface{}) (float64, bool) {
+	return galeraReplicationLatency(metrics, 1)
+}
+
+// galeraReplicationLatencyMax returns the maximum replication latency of wsrep_evs_repl_latency.
+func galeraReplicationLatencyMax(metrics map[string]interface{}) (float64, bool) {
+	return galeraReplicationLatency(metrics, 2)
+}
+
+func galeraReplicationLatency(metrics map[string]interface{}, field int) (float64, bool) {
+	latency, ok := metrics["wsrep_evs_repl_latency"]
+	if !ok {
+		return 0, false
+	}
+	fields := strings.Split(fmt.Sprint(latency), "/")
+	if len(fields) <= field {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(strings.TrimSpace(fields[field]), 64)
+	return value, err == nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGaleraData(t *testing.T) {
+	metrics := map[string]interface{}{
+		"node_type":                 "master",
+		"wsrep_cluster_size":        3,
+		"wsrep_cluster_status":      "Primary",
+		"wsrep_local_state":         2,
+		"wsrep_local_state_comment": "Donor/Desynced",
+		"wsrep_flow_control_paused": 0.25,
+		"wsrep_local_recv_queue":    7,
+		"wsrep_local_cert_failures": 4,
+		"wsrep_evs_repl_latency":    "0.000218/0.000394/0.000723/0.000131/26",
+	}
+	getGaleraData(map[string]interface{}{"wsrep_on": "ON"}, metrics)
+	assert.Equal(t, "master", metrics["node_type"])
+	assert.Equal(t, "donor/desynced", metrics["galera_state"])
+
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateMetrics(ms, metrics, "5.7.0", arguments.ArgumentList{})
+
+	assert.Equal(t, "master", ms.Metrics["cluster.nodeType"])
+	assert.Equal(t, "donor/desynced", ms.Metrics["cluster.galeraState"])
+	assert.Equal(t, 3., ms.Metrics["cluster.galeraSize"])
+	assert.Equal(t, "Primary", ms.Metrics["cluster.galeraStatus"])
+	assert.Equal(t, 0.25, ms.Metrics["cluster.galeraFlowControlPaused"])
+	assert.Equal(t, 7., ms.Metrics["cluster.galeraLocalRecvQueue"])
+	assert.Equal(t, 0.000394, ms.Metrics["cluster.galeraReplicationLatencyAvgSeconds"])
+	assert.Equal(t, 0.000723, ms.Metrics["cluster.galeraReplicationLatencyMaxSeconds"])
+	assert.Contains(t, ms.Metrics, "cluster.galeraLocalCertFailuresPerSecond")
+}
+
+func TestGetGaleraDataDisabled(t *testing.T) {
+	metrics := map[string]interface{}{"node_type": "master", "wsrep_local_state_comment": "Synced"}
+	getGaleraData(map[string]interface{}{"wsrep_on": "OFF"}, metrics)
+
+	assert.Equal(t, "master", metrics["node_type"])
+	assert.NotContains(t, metrics, "wsrep_on")
+	assert.NotContains(t, metrics, "galera_state")
+}
+
+func TestGetGaleraDataAsyncReplica(t *testing.T) {
+	// A Galera node replicating from an asynchronous source keeps its replication metrics
+	metrics := map[string]interface{}{"node_type": "slave", "Slave_IO_Running": "Yes", "Slave_SQL_Running": "Yes",
+		"wsrep_local_state_comment": "Synced"}
+	getGaleraData(map[string]interface{}{"wsrep_on": "ON"}, metrics)
+
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateMetrics(ms, metrics, "5.7.0", arguments.ArgumentList{})
+
+	assert.Equal(t, "slave", ms.Metrics["cluster.nodeType"])
+	assert.Equal(t, 1., ms.Metrics["cluster.slaveRunning"])
+	assert.Equal(t, "synced", ms.Metrics["cluster.galeraState"])
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+)
+
+// groupReplicationColumns are the member and member stats columns available on every Group Replication version.
+const groupReplicationColumns = `m.MEMBER_STATE AS group_member_state,
+	(SELECT COUNT(*) FROM performance_schema.replication_group_members) AS group_members,
+	(SELECT COUNT(*) FROM performance_schema.replication_group_members WHERE MEMBER_STATE = 'ONLINE') AS group_members_online,
+	s.COUNT_TRANSACTIONS_IN_QUEUE AS group_transactions_in_queue,
+	s.COUNT_TRANSACTIONS_CHECKED AS group_transactions_checked,
+	s.COUNT_CONFLICTS_DETECTED AS group_conflicts_detected,
+	s.COUNT_TRANSACTIONS_ROWS_VALIDATING AS group_transactions_rows_validating`
+
+const groupReplicationFrom = `
+	FROM performance_schema.replication_group_members m
+	LEFT JOIN performance_schema.replication_group_member_stats s ON s.MEMBER_ID = m.MEMBER_ID
+	WHERE m.MEMBER_ID = @@server_uuid`
+
+/*
+groupReplicationQuery returns the state of the local member of the group. MEMBER_ROLE and the applier and
+local transaction counters were added in MySQL 8.0.
+*/
+const groupReplicationQuery = `SELECT ` + groupReplicationColumns + `,
+	m.MEMBER_ROLE AS group_member_role,
+	s.COUNT_TRANSACTIONS_REMOTE_IN_APPLIER_QUEUE AS group_transactions_remote_in_applier_queue,
+	s.COUNT_TRANSACTIONS_REMOTE_APPLIED AS group_transactions_remote_applied,
+	s.COUNT_TRANSACTIONS_LOCAL_PROPOSED AS group_transactions_local_proposed,
+	s.COUNT_TRANSACTIONS_LOCAL_ROLLBACK AS group_transactions_local_rollback` + groupReplicationFrom
+
+const groupReplicationQueryBelowVersion8 = `SELECT ` + groupReplicationColumns + groupReplicationFrom
+
+// groupReplicationMetrics are reported in MysqlSample by the members of a replication group or InnoDB Cluster.
+var groupReplicationMetrics = map[string][]interface{}{
+	"cluster.groupName":                               {"group_replication_group_name", metric.ATTRIBUTE},
+	"cluster.groupMemberState":                        {"group_member_state", metric.ATTRIBUTE},
+	"cluster.groupMemberRole":                         {"group_member_role", metric.ATTRIBUTE},
+	"cluster.groupReplicationRole":                    {"group_replication_role", metric.ATTRIBUTE},
+	"cluster.groupFlowControlMode":                    {"group_replication_flow_control_mode", metric.ATTRIBUTE},
+	"cluster.groupMembers":                            {"group_members", metric.GAUGE},
+	"cluster.groupMembersOnline":                      {"group_members_online", metric.GAUGE},
+	"cluster.groupTransactionsInQueue":                {"group_transactions_in_queue", metric.GAUGE},
+	"cluster.groupTransactionsRowsValidating":         {"group_transactions_rows_validating", metric.GAUGE},
+	"cluster.groupTransactionsRemoteInApplierQueue":   {"group_transactions_remote_in_applier_queue", metric.GAUGE},
+	"cluster.groupTransactionsCheckedPerSecond":       {"group_transactions_checked", metric.PRATE},
+	"cluster.groupConflictsDetectedPerSecond":         {"group_conflicts_detected", metric.PRATE},
+	"cluster.groupTransactionsRemoteAppliedPerSecond": {"group_transactions_remote_applied", metric.PRATE},
+	"cluster.groupTransactionsLocalProposedPerSecond": {"group_transactions_local_proposed", metric.PRATE},
+	"cluster.groupTransactionsLocalRollbackPerSecond": {"group_transactions_local_rollback", metric.PRATE},
+	"cluster.groupFlowControlThrottlesPerSecond":      {"Gr_flow_control_throttle_count", metric.PRATE},
+	"cluster.groupFlowControlThrottleMicrosPerSecond": {"Gr_flow_control_throttle_time_sum", metric.PRATE},
+	"cluster.groupFlowControlThrottleActive":          {"Gr_flow_control_throttle_active_count", metric.GAUGE},
+}
+
+/*
+getGroupReplicationData adds the state of the local group member to the raw metrics, along with its role in the
+group as primary or secondary. The node type is left as detected from the replica status, so that members also
+replicating from an asynchronous source keep their replication metrics. Servers without the group_replication
+plugin, which defines group_replication_group_name, are left untouched.
+*/
+func getGroupReplicationData(db dataSource, dbVersion string, inventory map[string]interface{}, metrics map[string]interface{}) {
+	groupName, ok := inventory["group_replication_group_name"]
+	if !ok || fmt.Sprint(groupName) == "" {
+		return
+	}
+
+	query := groupReplicationQuery
+	if isDBVersionLessThan8(dbVersion) {
+		query = groupReplicationQueryBelowVersion8
+	}
+	rows, err := db.queryRows(query, "")
+	if err != nil {
+		log.Warn("Can't get group replication member state (performance_schema may not be enabled): %v", err)
+		return
+	}
+	if len(rows) == 0 {
+		return
+	}
+
+	for key, value := range rows[0] {
+		metrics[key] = value
+	}
+	metrics["group_replication_group_name"] = groupName
+	if mode, ok := inventory["group_replication_flow_control_mode"]; ok {
+		metrics["group_replication_flow_control_mode"] = mode
+	}
+
+	if _, ok := metrics["group_member_role"]; !ok {
+		metrics["group_member_role"] = groupMemberRoleBelowVersion8(inventory, metrics)
+	}
+	if role := strings.ToLower(fmt.Sprint(metrics["group_member_role"])); role == "primary" || role == "secondary" {
+		metrics["group_replication_role"] = role
+	}
+}
+
+// groupMemberRoleBelowVersion8 derives the role of the member on MySQL 5.7, where every member of a multi-primary
+// group is a primary and a single-primary group reports its primary in the status variables.
+func groupMemberRoleBelowVersion8(inventory map[string]interface{}, metrics map[string]interface{}) string {
+	singlePrimary := strings.ToUpper(fmt.Sprint(inventory["group_replication_single_primary_mode"]))
+	if singlePrimary == "OFF" || singlePrimary == "0" || singlePrimary == "FALSE" {
+		return "PRIMARY"
+	}
+	if fmt.Sprint(metrics["group_replication_primary_member"]) == fmt.Sprint(inventory["server_uuid"]) {
+		return "PRIMARY"
+	}
+	return "SECONDARY"
+}
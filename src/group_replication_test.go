@@ -0,0 +1,69 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetGroupReplicationDataSecondary(t *testing.T) {
+	db := &userDB{rows: []map[string]interface{}{
+		{"group_member_state": "ONLINE", "group_member_role": "SECONDARY", "group_members": 3, "group_members_online": 3,
+			"group_transactions_in_queue": 2, "group_transactions_checked": 1500, "group_conflicts_detected": 1,
+			"group_transactions_rows_validating": 12, "group_transactions_remote_in_applier_queue": 4},
+	}}
+	inventory := map[string]interface{}{
+		"group_replication_group_name":        "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee",
+		"group_replication_flow_control_mode": "QUOTA",
+	}
+	metrics := map[string]interface{}{"node_type": "slave", "Slave_IO_Running": "Yes", "Slave_SQL_Running": "Yes"}
+
+	getGroupReplicationData(db, "8.0.36", inventory, metrics)
+
+	assert.Equal(t, groupReplicationQuery, db.lastQuery)
+	assert.Equal(t, "secondary", metrics["group_replication_role"])
+
+	// A member replicating from an asynchronous source keeps its node type and replication metrics
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateMetrics(ms, metrics, "8.0.36", arguments.ArgumentList{})
+	assert.Equal(t, "slave", ms.Metrics["cluster.nodeType"])
+	assert.Equal(t, 1., ms.Metrics["cluster.slaveRunning"])
+	assert.Equal(t, "secondary", ms.Metrics["cluster.groupReplicationRole"])
+	assert.Equal(t, "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee", ms.Metrics["cluster.groupName"])
+	assert.Equal(t, "QUOTA", ms.Metrics["cluster.groupFlowControlMode"])
+	assert.Equal(t, "ONLINE", ms.Metrics["cluster.groupMemberState"])
+	assert.Equal(t, 4., ms.Metrics["cluster.groupTransactionsRemoteInApplierQueue"])
+	assert.NotContains(t, ms.Metrics, "cluster.groupFlowControlThrottleActive")
+}
+
+func TestGetGroupReplicationDataBelowVersion8(t *testing.T) {
+	db := &userDB{rows: []map[string]interface{}{{"group_member_state": "ONLINE", "group_members": 3}}}
+	inventory := map[string]interface{}{
+		"group_replication_group_name":          "aaaaaaaa-bbbb-cccc-dddd-eeeeeeeeeeee",
+		"group_replication_single_primary_mode": "ON",
+		"server_uuid":                           "11111111-2222-3333-4444-555555555555",
+	}
+	metrics := map[string]interface{}{"group_replication_primary_member": "11111111-2222-3333-4444-555555555555"}
+
+	getGroupReplicationData(db, "5.7.44", inventory, metrics)
+
+	assert.Equal(t, groupReplicationQueryBelowVersion8, db.lastQuery)
+	assert.Equal(t, "PRIMARY", metrics["group_member_role"])
+	assert.Equal(t, "primary", metrics["group_replication_role"])
+	assert.NotContains(t, metrics, "node_type")
+}
+
+func TestGetGroupReplicationDataWithoutPlugin(t *testing.T) {
+	db := &userDB{}
+	metrics := map[string]interface{}{"node_type": "master"}
+
+	getGroupReplicationData(db, "8.0.36", map[string]interface{}{}, metrics)
+
+	assert.Empty(t, db.lastQuery)
+	assert.Equal(t, "master", metrics["node_type"])
+	assert.NotContains(t, metrics, "group_replication_role")
+}
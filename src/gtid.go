@@ -0,0 +1,172 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+)
+
+var errInvalidGTIDSet = errors.New("invalid GTID set")
+
+// gtidInterval is a range of transaction numbers of a source, both ends included.
+type gtidInterval struct {
+	start, end uint64
+}
+
+/*
+gtidSet holds the sorted and merged transaction intervals of every source of a GTID set, such as
+`3e11fa47-71ca-11e1-9e33-c80aa9429562:1-5:7, 4a6b7c2d-71ca-11e1-9e33-c80aa9429562:1-3`. Tagged GTIDs of MySQL 8.3
+(`uuid:tag:1-5`) are kept as a separate source per tag.
+*/
+type gtidSet map[string][]gtidInterval
+
+// parseGTIDSet parses a GTID set as reported by SHOW REPLICA STATUS or gtid_executed, where sources are separated by
+// commas and, in some versions, new lines.
+func parseGTIDSet(value string) (gtidSet, error) {
+	set := gtidSet{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		fields := strings.Split(part, ":")
+		source := strings.ToLower(strings.TrimSpace(fields[0]))
+		if len(fields) < 2 || source == "" {
+			return nil, fmt.Errorf("%w: %q", errInvalidGTIDSet, part)
+		}
+
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if field == "" {
+				return nil, fmt.Errorf("%w: %q", errInvalidGTIDSet, part)
+			}
+			if field[0] < '0' || field[0] > '9' {
+				// A tag applies to the intervals that follow it
+				source = strings.ToLower(strings.TrimSpace(fields[0]) + ":" + field)
+				continue
+			}
+
+			interval, err := parseGTIDInterval(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %q", errInvalidGTIDSet, part)
+			}
+			set[source] = append(set[source], interval)
+		}
+	}
+
+	for source, intervals := range set {
+		set[source] = mergeGTIDIntervals(intervals)
+	}
+	return set, nil
+}
+
+// parseGTIDInterval parses a single transaction number or a `start-end` range.
+func parseGTIDInterval(value string) (gtidInterval, error) {
+	start, end, isRange := strings.Cut(value, "-")
+	first, err := strconv.ParseUint(start, 10, 64)
+	if err != nil {
+		return gtidInterval{}, err
+	}
+	if !isRange {
+		return gtidInterval{start: first, end: first}, nil
+	}
+
+	last, err := strconv.ParseUint(end, 10, 64)
+	if err != nil {
+		return gtidInterval{}, err
+	}
+	if last < first {
+		return gtidInterval{}, errInvalidGTIDSet
+	}
+	return gtidInterval{start: first, end: last}, nil
+}
+
+// mergeGTIDIntervals sorts the intervals and merges the ones that overlap or are adjacent.
+func mergeGTIDIntervals(intervals []gtidInterval) []gtidInterval {
+	sort.Slice(intervals, func(i, j int) bool { return intervals[i].start < intervals[j].start })
+
+	merged := make([]gtidInterval, 0, len(intervals))
+	for _, interval := range intervals {
+		last := len(merged) - 1
+		if last >= 0 && interval.start <= merged[last].end+1 {
+			if interval.end > merged[last].end {
+				merged[last].end = interval.end
+			}
+			continue
+		}
+		merged = append(merged, interval)
+	}
+	return merged
+}
+
+// subtract returns the transactions of the set that are not in other.
+func (s gtidSet) subtract(other gtidSet) gtidSet {
+	result := gtidSet{}
+	for source, intervals := range s {
+		remaining := subtractGTIDIntervals(intervals, other[source])
+		if len(remaining) > 0 {
+			result[source] = remaining
+		}
+	}
+	return result
+}
+
+// subtractGTIDIntervals removes the sorted and merged intervals of b from the sorted and merged intervals of a.
+func subtractGTIDIntervals(a, b []gtidInterval) []gtidInterval {
+	var result []gtidInterval
+	for _, interval := range a {
+		start, covered := interval.start, false
+		for _, removed := range b {
+			if removed.end < start {
+				continue
+			}
+			if removed.start > interval.end {
+				break
+			}
+			if removed.start > start {
+				result = append(result, gtidInterval{start: start, end: removed.start - 1})
+			}
+			if removed.end >= interval.end {
+				covered = true
+				break
+			}
+			start = removed.end + 1
+		}
+		if !covered {
+			result = append(result, gtidInterval{start: start, end: interval.end})
+		}
+	}
+	return result
+}
+
+// count returns the number of transactions of the set.
+func (s gtidSet) count() uint64 {
+	var total uint64
+	for _, intervals := range s {
+		for _, interval := range intervals {
+			total += interval.end - interval.start + 1
+		}
+	}
+	return total
+}
+
+// gtidTransactionsNotExecuted returns the number of transactions received by the replica, in Retrieved_Gtid_Set,
+// that are not applied yet, in Executed_Gtid_Set.
+func gtidTransactionsNotExecuted(metrics map[string]interface{}) (float64, bool) {
+	retrieved, err := parseGTIDSet(fmt.Sprint(metrics["Retrieved_Gtid_Set"]))
+	if err != nil {
+		log.Warn("Can't parse Retrieved_Gtid_Set: %v", err)
+		return 0, false
+	}
+	executed, err := parseGTIDSet(fmt.Sprint(metrics["Executed_Gtid_Set"]))
+	if err != nil {
+		log.Warn("Can't parse Executed_Gtid_Set: %v", err)
+		return 0, false
+	}
+	return float64(retrieved.subtract(executed).count()), true
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	sourceUUID  = "3e11fa47-71ca-11e1-9e33-c80aa9429562"
+	replicaUUID = "4a6b7c2d-71ca-11e1-9e33-c80aa9429562"
+)
+
+func TestParseGTIDSet(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected gtidSet
+	}{
+		{"empty", "", gtidSet{}},
+		{"single transaction", sourceUUID + ":7", gtidSet{sourceUUID: {{7, 7}}}},
+		{"intervals", sourceUUID + ":1-5:7-9", gtidSet{sourceUUID: {{1, 5}, {7, 9}}}},
+		{"merged intervals", sourceUUID + ":7-9:1-5:6:8-12", gtidSet{sourceUUID: {{1, 12}}}},
+		{"uppercase uuid", "3E11FA47-71CA-11E1-9E33-C80AA9429562:1-3", gtidSet{sourceUUID: {{1, 3}}}},
+		{
+			"several sources on several lines",
+			sourceUUID + ":1-100,\n" + replicaUUID + ":1-3",
+			gtidSet{sourceUUID: {{1, 100}}, replicaUUID: {{1, 3}}},
+		},
+		{
+			"tagged transactions",
+			sourceUUID + ":1-5:batch:1-2:4",
+			gtidSet{sourceUUID: {{1, 5}}, sourceUUID + ":batch": {{1, 2}, {4, 4}}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			set, err := parseGTIDSet(test.value)
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, set)
+		})
+	}
+}
+
+func TestParseGTIDSetInvalid(t *testing.T) {
+	for _, value := range []string{sourceUUID, sourceUUID + ":", sourceUUID + ":5-1", sourceUUID + ":1-x", ":1-5"} {
+		_, err := parseGTIDSet(value)
+		assert.ErrorIs(t, err, errInvalidGTIDSet, value)
+	}
+}
+
+func TestGTIDSetSubtract(t *testing.T) {
+	tests := []struct {
+		name          string
+		set, other    string
+		expected      gtidSet
+		expectedCount uint64
+	}{
+		{"all applied", sourceUUID + ":1-100", sourceUUID + ":1-100," + replicaUUID + ":1-5", gtidSet{}, 0},
+		{"nothing applied", sourceUUID + ":1-100", "", gtidSet{sourceUUID: {{1, 100}}}, 100},
+		{"tail not applied", sourceUUID + ":1-100", sourceUUID + ":1-90", gtidSet{sourceUUID: {{91, 100}}}, 10},
+		{"head not applied", sourceUUID + ":1-100", sourceUUID + ":11-100", gtidSet{sourceUUID: {{1, 10}}}, 10},
+		{
+			"gaps not applied",
+			sourceUUID + ":1-100",
+			sourceUUID + ":1-10:20-30:95-200",
+			gtidSet{sourceUUID: {{11, 19}, {31, 94}}},
+			73,
+		},
+		{
+			"several intervals",
+			sourceUUID + ":1-10:20-30",
+			sourceUUID + ":5-25",
+			gtidSet{sourceUUID: {{1, 4}, {26, 30}}},
+			9,
+		},
+		{
+			"other source",
+			sourceUUID + ":1-10," + replicaUUID + ":1-3",
+			replicaUUID + ":1-10",
+			gtidSet{sourceUUID: {{1, 10}}},
+			10,
+		},
+		{"tags are separate sources", sourceUUID + ":batch:1-4", sourceUUID + ":1-4", gtidSet{sourceUUID + ":batch": {{1, 4}}}, 4},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			set, err := parseGTIDSet(test.set)
+			require.NoError(t, err)
+			other, err := parseGTIDSet(test.other)
+			require.NoError(t, err)
+
+			result := set.subtract(other)
+			assert.Equal(t, test.expected, result)
+			assert.Equal(t, test.expectedCount, result.count())
+		})
+	}
+}
+
+func TestGTIDTransactionsNotExecuted(t *testing.T) {
+	count, ok := gtidTransactionsNotExecuted(map[string]interface{}{
+		"Retrieved_Gtid_Set": sourceUUID + ":1-120",
+		"Executed_Gtid_Set":  sourceUUID + ":1-100,\n" + replicaUUID + ":1-3",
+	})
+	assert.True(t, ok)
+	assert.Equal(t, 20., count)
+
+	_, ok = gtidTransactionsNotExecuted(map[string]interface{}{
+		"Retrieved_Gtid_Set": "not a gtid set",
+		"Executed_Gtid_Set":  sourceUUID + ":1-100",
+	})
+	assert.False(t, ok)
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+var errInvalidHeartbeat = errors.New("invalid heartbeat timestamp")
+
+// heartbeatTimestampLayouts are the formats of the pt-heartbeat ts column and of UTC_TIMESTAMP(6).
+var heartbeatTimestampLayouts = []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05"}
+
+/*
+heartbeatQuery returns the latest heartbeat written by pt-heartbeat, or by the source with HeartbeatServerID
+when set, along with the UTC time of the replica so that the lag does not depend on the clock of the
+integration host. pt-heartbeat must run with --utc.
+*/
+func heartbeatQuery(args arguments.ArgumentList) string {
+	query := fmt.Sprintf("SELECT ts, UTC_TIMESTAMP(6) AS now FROM %s.%s",
+		quoteIdentifier(args.HeartbeatSchema), quoteIdentifier(args.HeartbeatTable))
+	if args.HeartbeatServerID > 0 {
+		query += " WHERE server_id = ?"
+	}
+	return query + " ORDER BY ts DESC LIMIT 1"
+}
+
+// populateHeartbeatLag reports the replication lag measured with a pt-heartbeat table as cluster.heartbeatLagSeconds.
+func populateHeartbeatLag(ms *metric.Set, db dataSource, args arguments.ArgumentList) {
+	var queryArgs []interface{}
+	if args.HeartbeatServerID > 0 {
+		queryArgs = append(queryArgs, args.HeartbeatServerID)
+	}
+
+	rows, err := db.queryRows(heartbeatQuery(args), "", queryArgs...)
+	if err != nil {
+		log.Warn("Can't get replication heartbeat from %s.%s: %v", args.HeartbeatSchema, args.HeartbeatTable, err)
+		return
+	}
+	if len(rows) == 0 {
+		log.Warn("No replication heartbeat found in %s.%s", args.HeartbeatSchema, args.HeartbeatTable)
+		return
+	}
+
+	lag, err := heartbeatLag(rows[0])
+	if err != nil {
+		log.Warn("Can't compute replication heartbeat lag: %v", err)
+		return
+	}
+	if err := ms.SetMetric("cluster.heartbeatLagSeconds", lag, metric.GAUGE); err != nil {
+		log.Warn("Error setting value: %s", err)
+	}
+}
+
+// heartbeatLag returns the seconds elapsed between the heartbeat and the current UTC time of the replica.
+// Heartbeats in the future, caused by clock skew between servers, are reported as no lag.
+func heartbeatLag(row map[string]interface{}) (float64, error) {
+	heartbeat, err := parseHeartbeatTimestamp(row["ts"])
+	if err != nil {
+		return 0, err
+	}
+	now, err := parseHeartbeatTimestamp(row["now"])
+	if err != nil {
+		return 0, err
+	}
+
+	lag := now.Sub(heartbeat).Seconds()
+	if lag < 0 {
+		log.Debug("Replication heartbeat %s is ahead of the replica clock %s", heartbeat, now)
+		return 0, nil
+	}
+	return lag, nil
+}
+
+func parseHeartbeatTimestamp(value interface{}) (time.Time, error) {
+	text := fmt.Sprint(value)
+	for _, layout := range heartbeatTimestampLayouts {
+		if timestamp, err := time.Parse(layout, text); err == nil {
+			return timestamp, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %q", errInvalidHeartbeat, text)
+}
+
+// quoteIdentifier quotes a schema or table name with backticks.
+func quoteIdentifier(name string) string {
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
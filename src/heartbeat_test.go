@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatQuery(t *testing.T) {
+	args := arguments.ArgumentList{HeartbeatSchema: "percona", HeartbeatTable: "heart`beat"}
+	assert.Equal(t, "SELECT ts, UTC_TIMESTAMP(6) AS now FROM `percona`.`heart``beat` ORDER BY ts DESC LIMIT 1", heartbeatQuery(args))
+
+	args.HeartbeatServerID = 10
+	assert.Equal(t, "SELECT ts, UTC_TIMESTAMP(6) AS now FROM `percona`.`heart``beat` WHERE server_id = ? ORDER BY ts DESC LIMIT 1", heartbeatQuery(args))
+}
+
+func TestHeartbeatLag(t *testing.T) {
+	lag, err := heartbeatLag(map[string]interface{}{"ts": "2026-09-14T10:15:42.250000", "now": "2026-09-14 10:15:45.750000"})
+	require.NoError(t, err)
+	assert.InDelta(t, 3.5, lag, 0.0001)
+
+	lag, err = heartbeatLag(map[string]interface{}{"ts": "2026-09-14T10:15:46", "now": "2026-09-14 10:15:45"})
+	require.NoError(t, err)
+	assert.Zero(t, lag)
+
+	_, err = heartbeatLag(map[string]interface{}{"ts": "yesterday", "now": "2026-09-14 10:15:45"})
+	assert.ErrorIs(t, err, errInvalidHeartbeat)
+}
+
+func TestPopulateHeartbeatLag(t *testing.T) {
+	db := &userDB{rows: []map[string]interface{}{{"ts": "2026-09-14T10:15:40", "now": "2026-09-14 10:15:42"}}}
+	ms := metric.NewSet("MysqlSample", nil)
+	populateHeartbeatLag(ms, db, arguments.ArgumentList{HeartbeatSchema: "percona", HeartbeatTable: "heartbeat", HeartbeatServerID: 1})
+
+	assert.Equal(t, []interface{}{1}, db.args)
+	assert.Equal(t, 2., ms.Metrics["cluster.heartbeatLagSeconds"])
+}
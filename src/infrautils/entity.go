@@ -24,19 +24,29 @@ func CreateNodeEntity(
 	return i.LocalEntity(), nil
 }
 
+// CreateDatabaseEntity creates a new integration entity for a database (schema) of a MySQL node.
+func CreateDatabaseEntity(i *integration.Integration, hostname string, port int, database string) (*integration.Entity, error) {
+	return i.Entity(fmt.Sprint(hostname, ":", port, "/", database), constants.DatabaseEntityType)
+}
+
 // MetricSet creates a new metric set with the given attributes.
-func MetricSet(e *integration.Entity, eventType, hostname string, port int, remoteMonitoring bool) *metric.Set {
+// Extra attributes identify the sample among others of the same event type, such as the database of a table,
+// and namespace its RATE and DELTA metrics accordingly.
+func MetricSet(e *integration.Entity, eventType, hostname string, port int, remoteMonitoring bool, attributes ...attribute.Attribute) *metric.Set {
 	if remoteMonitoring {
 		return e.NewMetricSet(
 			eventType,
-			attribute.Attr("hostname", hostname),
-			attribute.Attr("port", strconv.Itoa(port)),
+			append([]attribute.Attribute{
+				attribute.Attr("hostname", hostname),
+				attribute.Attr("port", strconv.Itoa(port)),
+			}, attributes...)...,
 		)
 	}
-
 	return e.NewMetricSet(
 		eventType,
-		attribute.Attr("port", strconv.Itoa(port)),
+		append([]attribute.Attribute{
+			attribute.Attr("port", strconv.Itoa(port)),
+		}, attributes...)...,
 	)
 }
 
@@ -3,6 +3,7 @@ package infrautils
 import (
 	"testing"
 
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
 	"github.com/newrelic/infra-integrations-sdk/v3/integration"
 	"github.com/stretchr/testify/assert"
 )
@@ -53,3 +54,14 @@ func TestMetricSet(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricSetExtraAttributes(t *testing.T) {
+	i, _ := integration.New("test", "1.0.0")
+	entity := i.LocalEntity()
+
+	metricSet := MetricSet(entity, "testEvent", "remotehost", 3306, true, attribute.Attr("database", "app"))
+
+	assert.Equal(t, "remotehost", metricSet.Metrics["hostname"])
+	assert.Equal(t, "3306", metricSet.Metrics["port"])
+	assert.Equal(t, "app", metricSet.Metrics["database"])
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+// innodbCountersQuery reads every InnoDB counter. The enabled state is checked per row because MySQL reports it in
+// the STATUS column and MariaDB 10.5+ in the ENABLED column.
+const innodbCountersQuery = "SELECT * FROM information_schema.INNODB_METRICS"
+
+const innodbCounterPrefix = "db.innodb.metrics."
+
+// innodbCounterTypes maps the TYPE column of INNODB_METRICS to the metric type reported. Module owners
+// (set_owner) only group other counters and are skipped.
+var innodbCounterTypes = map[string]metric.SourceType{
+	"counter":        metric.PRATE,
+	"status_counter": metric.PRATE,
+	"set_member":     metric.PRATE,
+	"value":          metric.GAUGE,
+}
+
+// populateInnodbCounters reports every enabled counter of information_schema.INNODB_METRICS, restricted to the
+// subsystems listed in InnodbCounterSubsystems when set, as db.innodb.metrics.* metrics of the given sample.
+func populateInnodbCounters(ms *metric.Set, db dataSource, args arguments.ArgumentList) {
+	subsystems, err := parseInnodbSubsystems(args.InnodbCounterSubsystems)
+	if err != nil {
+		log.Warn("Invalid InnoDB counter subsystems %s, skipping InnoDB counters: %v", args.InnodbCounterSubsystems, err)
+		return
+	}
+
+	query := innodbCountersQuery
+	queryArgs := make([]interface{}, 0, len(subsystems))
+	if len(subsystems) > 0 {
+		query += fmt.Sprintf(" WHERE SUBSYSTEM IN (%s)", placeholders(len(subsystems)))
+		for _, subsystem := range subsystems {
+			queryArgs = append(queryArgs, subsystem)
+		}
+	}
+
+	rows, err := db.queryRows(query, "", queryArgs...)
+	if err != nil {
+		log.Warn("Can't get InnoDB counters: %v", err)
+		return
+	}
+
+	for _, row := range rows {
+		if !isInnodbCounterEnabled(row) {
+			continue
+		}
+		metricType, ok := innodbCounterTypes[fmt.Sprint(row["TYPE"])]
+		if !ok {
+			continue
+		}
+		value, ok := row["COUNT"]
+		if !ok {
+			continue
+		}
+
+		name := innodbCounterMetricName(fmt.Sprint(row["NAME"]), metricType)
+		if err := ms.SetMetric(name, value, metricType); err != nil {
+			log.Warn("Error setting value: %s", err)
+		}
+	}
+}
+
+// parseInnodbSubsystems parses the JSON array of subsystems to collect. An empty value collects every subsystem.
+func parseInnodbSubsystems(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	var subsystems []string
+	if err := json.Unmarshal([]byte(value), &subsystems); err != nil {
+		return nil, err
+	}
+	return subsystems, nil
+}
+
+func isInnodbCounterEnabled(row map[string]interface{}) bool {
+	if status, ok := row["STATUS"]; ok {
+		return fmt.Sprint(status) == "enabled"
+	}
+	if enabled, ok := row["ENABLED"]; ok {
+		return fmt.Sprint(enabled) == "1" || enabled == true
+	}
+	return false
+}
+
+// innodbCounterMetricName converts a counter name such as adaptive_hash_searches to
+// db.innodb.metrics.adaptiveHashSearchesPerSecond, following the naming of the other InnoDB metrics.
+func innodbCounterMetricName(counter string, metricType metric.SourceType) string {
+	parts := strings.Split(strings.ToLower(counter), "_")
+	var name strings.Builder
+	name.WriteString(innodbCounterPrefix)
+	for idx, part := range parts {
+		if part == "" {
+			continue
+		}
+		if idx > 0 {
+			part = strings.ToUpper(part[:1]) + part[1:]
+		}
+		name.WriteString(part)
+	}
+	if metricType == metric.PRATE {
+		name.WriteString("PerSecond")
+	}
+	return name.String()
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInnodbCounterMetricName(t *testing.T) {
+	assert.Equal(t, "db.innodb.metrics.adaptiveHashSearchesPerSecond", innodbCounterMetricName("adaptive_hash_searches", metric.PRATE))
+	assert.Equal(t, "db.innodb.metrics.bufferPoolSize", innodbCounterMetricName("buffer_pool_size", metric.GAUGE))
+}
+
+func TestPopulateInnodbCounters(t *testing.T) {
+	db := &userDB{rows: []map[string]interface{}{
+		{"NAME": "dml_reads", "SUBSYSTEM": "dml", "TYPE": "status_counter", "STATUS": "enabled", "COUNT": 100},
+		{"NAME": "buffer_pool_size", "SUBSYSTEM": "server", "TYPE": "value", "STATUS": "enabled", "COUNT": 134217728},
+		{"NAME": "lock_deadlocks", "SUBSYSTEM": "lock", "TYPE": "counter", "STATUS": "disabled", "COUNT": 0},
+		{"NAME": "module_dml", "SUBSYSTEM": "dml", "TYPE": "set_owner", "STATUS": "enabled", "COUNT": nil},
+		{"NAME": "purge_del_mark_records", "SUBSYSTEM": "purge", "TYPE": "counter", "ENABLED": 1, "COUNT": 7},
+	}}
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateInnodbCounters(ms, db, arguments.ArgumentList{InnodbCounterSubsystems: `["dml","server","lock","purge"]`})
+
+	assert.Equal(t, innodbCountersQuery+" WHERE SUBSYSTEM IN (?, ?, ?, ?)", db.lastQuery)
+	assert.Equal(t, []interface{}{"dml", "server", "lock", "purge"}, db.args)
+	assert.Contains(t, ms.Metrics, "db.innodb.metrics.dmlReadsPerSecond")
+	assert.Contains(t, ms.Metrics, "db.innodb.metrics.purgeDelMarkRecordsPerSecond")
+	assert.Equal(t, 134217728., ms.Metrics["db.innodb.metrics.bufferPoolSize"])
+	assert.NotContains(t, ms.Metrics, "db.innodb.metrics.lockDeadlocksPerSecond")
+	assert.NotContains(t, ms.Metrics, "db.innodb.metrics.moduleDmlPerSecond")
+}
+
+func TestPopulateInnodbCountersInvalidSubsystems(t *testing.T) {
+	db := &userDB{}
+	ms := metric.NewSet("MysqlSample", nil)
+	populateInnodbCounters(ms, db, arguments.ArgumentList{InnodbCounterSubsystems: "dml"})
+
+	assert.Empty(t, db.lastQuery)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+)
+
+const innodbStatusQuery = "SHOW ENGINE INNODB STATUS"
+
+var (
+	reservationCountRegex = regexp.MustCompile(`reservation count (\d+)`)
+	signalCountRegex      = regexp.MustCompile(`signal count (\d+)`)
+	semaphoreWaitRegex    = regexp.MustCompile(`^--Thread \d+ has waited at .* for ([\d.]+) seconds the semaphore`)
+	historyListRegex      = regexp.MustCompile(`^History list length (\d+)`)
+	pendingAioRegex       = regexp.MustCompile(`^Pending normal aio reads: *\[?([\d, ]*)\]? *, aio writes: *\[?([\d, ]*)\]?`)
+	pendingFsyncRegex     = regexp.MustCompile(`^Pending flushes \(fsync\) log: (\d+); buffer pool: (\d+)`)
+	ibufRegex             = regexp.MustCompile(`^Ibuf: size (\d+), free list len (\d+), seg size (\d+), (\d+) merges`)
+	ibufOperationsRegex   = regexp.MustCompile(`^insert (\d+), delete mark (\d+), delete (\d+)`)
+	logPositionRegex      = regexp.MustCompile(`^(Log sequence number|Log flushed up to|Pages flushed up to|Last checkpoint at) +(\d+)`)
+	pendingLogRegex       = regexp.MustCompile(`^(\d+) pending log flushes, (\d+) pending chkp writes`)
+	pendingReadsRegex     = regexp.MustCompile(`^Pending reads +(\d+)`)
+	pendingWritesRegex    = regexp.MustCompile(`^Pending writes: LRU (\d+), flush list (\d+)`)
+	queriesRegex          = regexp.MustCompile(`^(\d+) queries inside InnoDB, (\d+) queries in queue`)
+	readViewsRegex        = regexp.MustCompile(`^(\d+) read views open inside InnoDB`)
+)
+
+// innodbStatusMetrics are the metrics parsed from SHOW ENGINE INNODB STATUS which are not exposed as status variables
+// on every server version.
+var innodbStatusMetrics = map[string][]interface{}{
+	"db.innodb.semaphoreWaits":                   {"semaphore_waits", metric.GAUGE},
+	"db.innodb.semaphoreWaitMaxSeconds":          {"semaphore_wait_max_seconds", metric.GAUGE},
+	"db.innodb.osWaitReservationCount":           {"os_wait_reservation_count", metric.GAUGE},
+	"db.innodb.osWaitSignalCount":                {"os_wait_signal_count", metric.GAUGE},
+	"db.innodb.historyListLength":                {"history_list_length", metric.GAUGE},
+	"db.innodb.activeTransactions":               {"active_transactions", metric.GAUGE},
+	"db.innodb.lockWaitTransactions":             {"lock_wait_transactions", metric.GAUGE},
+	"db.innodb.pendingAioReads":                  {"pending_aio_reads", metric.GAUGE},
+	"db.innodb.pendingAioWrites":                 {"pending_aio_writes", metric.GAUGE},
+	"db.innodb.pendingLogFsyncs":                 {"pending_log_fsyncs", metric.GAUGE},
+	"db.innodb.pendingBufferPoolFsyncs":          {"pending_buffer_pool_fsyncs", metric.GAUGE},
+	"db.innodb.ibufSize":                         {"ibuf_size", metric.GAUGE},
+	"db.innodb.ibufFreeListLength":               {"ibuf_free_list_length", metric.GAUGE},
+	"db.innodb.ibufSegmentSize":                  {"ibuf_segment_size", metric.GAUGE},
+	"db.innodb.ibufMerges":                       {"ibuf_merges", metric.GAUGE},
+	"db.innodb.ibufMergedInserts":                {"ibuf_merged_inserts", metric.GAUGE},
+	"db.innodb.ibufMergedDeleteMarks":            {"ibuf_merged_delete_marks", metric.GAUGE},
+	"db.innodb.ibufMergedDeletes":                {"ibuf_merged_deletes", metric.GAUGE},
+	"db.innodb.logSequenceNumber":                {"log_sequence_number", metric.GAUGE},
+	"db.innodb.checkpointAgeBytes":               {"checkpoint_age", metric.GAUGE},
+	"db.innodb.logUnflushedBytes":                {"log_unflushed", metric.GAUGE},
+	"db.innodb.modifiedPagesAgeBytes":            {"modified_pages_age", metric.GAUGE},
+	"db.innodb.pendingLogFlushes":                {"pending_log_flushes", metric.GAUGE},
+	"db.innodb.pendingCheckpointWrites":          {"pending_checkpoint_writes", metric.GAUGE},
+	"db.innodb.bufferPoolPendingReads":           {"pending_reads", metric.GAUGE},
+	"db.innodb.bufferPoolPendingWritesLru":       {"pending_writes_lru", metric.GAUGE},
+	"db.innodb.bufferPoolPendingWritesFlushList": {"pending_writes_flush_list", metric.GAUGE},
+	"db.innodb.queriesInside":                    {"queries_inside", metric.GAUGE},
+	"db.innodb.queriesQueued":                    {"queries_queued", metric.GAUGE},
+	"db.innodb.readViews":                        {"read_views", metric.GAUGE},
+}
+
+// innodbStatusParsers parse the lines of every section of the monitor output into raw metrics.
+var innodbStatusParsers = map[string]func(line string, raw map[string]interface{}){
+	"SEMAPHORES":                            parseSemaphoresLine,
+	"TRANSACTIONS":                          parseTransactionsLine,
+	"FILE I/O":                              parseFileIOLine,
+	"INSERT BUFFER AND ADAPTIVE HASH INDEX": parseInsertBufferLine,
+	"LOG":                                   parseLogLine,
+	"BUFFER POOL AND MEMORY":                parseBufferPoolLine,
+	"ROW OPERATIONS":                        parseRowOperationsLine,
+}
+
+// queryInnodbStatus returns the text of SHOW ENGINE INNODB STATUS, shared by the InnoDB status metrics and the
+// deadlock samples.
+func queryInnodbStatus(db dataSource) (string, bool) {
+	rows, err := db.queryRows(innodbStatusQuery, "")
+	if err != nil {
+		log.Warn("Can't get InnoDB status (the PROCESS privilege is required): %v", err)
+		return "", false
+	}
+	if len(rows) == 0 {
+		return "", false
+	}
+
+	status, ok := rows[0]["Status"].(string)
+	if !ok {
+		log.Warn("Unexpected InnoDB status output")
+		return "", false
+	}
+	return status, true
+}
+
+// populateInnodbStatus parses the output of SHOW ENGINE INNODB STATUS into gauges of the given sample.
+func populateInnodbStatus(ms *metric.Set, status string, dbVersion string) {
+	raw := parseInnodbStatus(status)
+	populatePartialMetrics(ms, raw, availableMetrics(innodbStatusMetrics, raw), dbVersion)
+}
+
+// parseInnodbStatus parses the monitor output of MySQL 5.7, 8.x and MariaDB. Every section starts with its title
+// between two lines of dashes. Values missing from the output are left out of the returned raw metrics.
+func parseInnodbStatus(status string) map[string]interface{} {
+	raw := map[string]interface{}{}
+
+	for section, lines := range splitInnodbStatusSections(status) {
+		parser, ok := innodbStatusParsers[section]
+		if !ok {
+			continue
+		}
+		for _, line := range lines {
+			parser(strings.TrimSpace(line), raw)
+		}
+	}
+
+	lsn, hasLSN := raw["log_sequence_number"].(int)
+	if checkpoint, ok := raw["last_checkpoint_at"].(int); ok && hasLSN {
+		raw["checkpoint_age"] = lsn - checkpoint
+	}
+	if flushed, ok := raw["log_flushed_up_to"].(int); ok && hasLSN {
+		raw["log_unflushed"] = lsn - flushed
+	}
+	if pagesFlushed, ok := raw["pages_flushed_up_to"].(int); ok && hasLSN {
+		raw["modified_pages_age"] = lsn - pagesFlushed
+	}
+	return raw
+}
+
+// splitInnodbStatusSections returns the lines of every section of the monitor output, indexed by section title.
+func splitInnodbStatusSections(status string) map[string][]string {
+	lines := strings.Split(strings.ReplaceAll(status, "\r\n", "\n"), "\n")
+	sections := map[string][]string{}
+
+	section := ""
+	for idx := 0; idx < len(lines); idx++ {
+		if idx+2 < len(lines) && isDashLine(lines[idx]) && isDashLine(lines[idx+2]) && !isDashLine(lines[idx+1]) {
+			section = strings.TrimSpace(lines[idx+1])
+			sections[section] = []string{}
+			idx += 2
+			continue
+		}
+		if section != "" {
+			sections[section] = append(sections[section], lines[idx])
+		}
+	}
+	return sections
+}
+
+func isDashLine(line string) bool {
+	line = strings.TrimSpace(line)
+	return len(line) >= 3 && strings.Trim(line, "-") == ""
+}
+
+func parseSemaphoresLine(line string, raw map[string]interface{}) {
+	if match := reservationCountRegex.FindStringSubmatch(line); match != nil {
+		raw["os_wait_reservation_count"] = atoi(match[1])
+	}
+	if match := signalCountRegex.FindStringSubmatch(line); match != nil {
+		raw["os_wait_signal_count"] = atoi(match[1])
+	}
+	if _, ok := raw["semaphore_waits"]; !ok {
+		raw["semaphore_waits"] = 0
+		raw["semaphore_wait_max_seconds"] = 0.
+	}
+	if match := semaphoreWaitRegex.FindStringSubmatch(line); match != nil {
+		raw["semaphore_waits"] = raw["semaphore_waits"].(int) + 1
+		if seconds, err := strconv.ParseFloat(match[1], 64); err == nil && seconds > raw["semaphore_wait_max_seconds"].(float64) {
+			raw["semaphore_wait_max_seconds"] = seconds
+		}
+	}
+}
+
+func parseTransactionsLine(line string, raw map[string]interface{}) {
+	if _, ok := raw["active_transactions"]; !ok {
+		raw["active_transactions"] = 0
+		raw["lock_wait_transactions"] = 0
+	}
+	switch {
+	case strings.HasPrefix(line, "---TRANSACTION ") && strings.Contains(line, ", ACTIVE"):
+		raw["active_transactions"] = raw["active_transactions"].(int) + 1
+	case strings.HasPrefix(line, "------- TRX HAS BEEN WAITING"):
+		raw["lock_wait_transactions"] = raw["lock_wait_transactions"].(int) + 1
+	}
+	if match := historyListRegex.FindStringSubmatch(line); match != nil {
+		raw["history_list_length"] = atoi(match[1])
+	}
+}
+
+func parseFileIOLine(line string, raw map[string]interface{}) {
+	if match := pendingAioRegex.FindStringSubmatch(line); match != nil {
+		raw["pending_aio_reads"] = sumList(match[1])
+		raw["pending_aio_writes"] = sumList(match[2])
+	}
+	if match := pendingFsyncRegex.FindStringSubmatch(line); match != nil {
+		raw["pending_log_fsyncs"] = atoi(match[1])
+		raw["pending_buffer_pool_fsyncs"] = atoi(match[2])
+	}
+}
+
+func parseInsertBufferLine(line string, raw map[string]interface{}) {
+	if match := ibufRegex.FindStringSubmatch(line); match != nil {
+		raw["ibuf_size"] = atoi(match[1])
+		raw["ibuf_free_list_length"] = atoi(match[2])
+		raw["ibuf_segment_size"] = atoi(match[3])
+		raw["ibuf_merges"] = atoi(match[4])
+	}
+	// The merged operations are printed before the discarded ones, which are ignored.
+	if match := ibufOperationsRegex.FindStringSubmatch(line); match != nil {
+		if _, ok := raw["ibuf_merged_inserts"]; !ok {
+			raw["ibuf_merged_inserts"] = atoi(match[1])
+			raw["ibuf_merged_delete_marks"] = atoi(match[2])
+			raw["ibuf_merged_deletes"] = atoi(match[3])
+		}
+	}
+}
+
+func parseLogLine(line string, raw map[string]interface{}) {
+	if match := logPositionRegex.FindStringSubmatch(line); match != nil {
+		key := strings.ReplaceAll(strings.ToLower(match[1]), " ", "_")
+		raw[key] = atoi(match[2])
+	}
+	if match := pendingLogRegex.FindStringSubmatch(line); match != nil {
+		raw["pending_log_flushes"] = atoi(match[1])
+		raw["pending_checkpoint_writes"] = atoi(match[2])
+	}
+}
+
+func parseBufferPoolLine(line string, raw map[string]interface{}) {
+	if match := pendingReadsRegex.FindStringSubmatch(line); match != nil {
+		raw["pending_reads"] = atoi(match[1])
+	}
+	if match := pendingWritesRegex.FindStringSubmatch(line); match != nil {
+		raw["pending_writes_lru"] = atoi(match[1])
+		raw["pending_writes_flush_list"] = atoi(match[2])
+	}
+}
+
+func parseRowOperationsLine(line string, raw map[string]interface{}) {
+	if match := queriesRegex.FindStringSubmatch(line); match != nil {
+		raw["queries_inside"] = atoi(match[1])
+		raw["queries_queued"] = atoi(match[2])
+	}
+	if match := readViewsRegex.FindStringSubmatch(line); match != nil {
+		raw["read_views"] = atoi(match[1])
+	}
+}
+
+// sumList adds up a comma separated list of numbers, such as the pending requests of every I/O thread.
+func sumList(list string) int {
+	total := 0
+	for _, value := range strings.Split(list, ",") {
+		total += atoi(strings.TrimSpace(value))
+	}
+	return total
+}
+
+func atoi(value string) int {
+	i, _ := strconv.Atoi(value)
+	return i
+}
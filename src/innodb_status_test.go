@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateGolden = flag.Bool("update", false, "update the golden files of the InnoDB status parser")
+
+// TestParseInnodbStatusGolden parses captured monitor outputs and compares the raw metrics with the golden files.
+// Run `go test -run TestParseInnodbStatusGolden -update` to regenerate them.
+func TestParseInnodbStatusGolden(t *testing.T) {
+	inputs, err := filepath.Glob(filepath.Join("testdata", "innodb_status", "*.txt"))
+	require.NoError(t, err)
+	require.NotEmpty(t, inputs)
+
+	for _, input := range inputs {
+		t.Run(filepath.Base(input), func(t *testing.T) {
+			status, err := os.ReadFile(input)
+			require.NoError(t, err)
+
+			actual, err := json.MarshalIndent(parseInnodbStatus(string(status)), "", "  ")
+			require.NoError(t, err)
+			actual = append(actual, '\n')
+
+			golden := strings.TrimSuffix(input, ".txt") + ".golden.json"
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(golden, actual, 0o600))
+			}
+			expected, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(expected), string(actual))
+		})
+	}
+}
+
+func TestSplitInnodbStatusSections(t *testing.T) {
+	sections := splitInnodbStatusSections("----\nLOG\n----\nLog sequence number 10\n------------------\n---TRANSACTION 1\n")
+
+	require.Contains(t, sections, "LOG")
+	assert.Equal(t, []string{"Log sequence number 10", "------------------", "---TRANSACTION 1", ""}, sections["LOG"])
+}
+
+func TestPopulateInnodbStatus(t *testing.T) {
+	status, err := os.ReadFile(filepath.Join("testdata", "innodb_status", "mysql57.txt"))
+	require.NoError(t, err)
+
+	db := &userDB{rows: []map[string]interface{}{{"Type": "InnoDB", "Name": "", "Status": string(status)}}}
+	text, ok := queryInnodbStatus(db)
+	require.True(t, ok)
+	assert.Equal(t, innodbStatusQuery, db.lastQuery)
+
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateInnodbStatus(ms, text, "5.7.44")
+
+	assert.Equal(t, 1523., ms.Metrics["db.innodb.historyListLength"])
+	assert.Equal(t, 15872., ms.Metrics["db.innodb.checkpointAgeBytes"])
+	assert.Equal(t, 2., ms.Metrics["db.innodb.semaphoreWaits"])
+	assert.Equal(t, 12., ms.Metrics["db.innodb.semaphoreWaitMaxSeconds"])
+	assert.Equal(t, 1., ms.Metrics["db.innodb.pendingLogFlushes"])
+	assert.Equal(t, 3., ms.Metrics["db.innodb.readViews"])
+}
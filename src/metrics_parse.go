@@ -10,6 +10,7 @@ import (
 	"github.com/newrelic/infra-integrations-sdk/v3/data/inventory"
 	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
 	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
 )
 
 const (
@@ -23,6 +24,9 @@ const (
 	replicaQueryForVersion8Point4AndAbove = "SHOW REPLICA STATUS"
 	dbVersionQuery                        = "SELECT VERSION() as version;"
 
+	// replicaQueryMariaDB returns every replication connection of multi-source replicas on MariaDB
+	replicaQueryMariaDB = "SHOW ALL SLAVES STATUS"
+
 	dbMajorVersionThreshold = 8
 	dbMinorVersionThreshold = 4
 )
@@ -82,7 +86,7 @@ func asValue(value string) interface{} {
 	return value
 }
 
-func getRawData(db dataSource) (map[string]interface{}, map[string]interface{}, string, error) {
+func getRawData(db dataSource, args arguments.ArgumentList) (map[string]interface{}, map[string]interface{}, string, error) {
 
 	dbVersion := checkDBServerAndGetDBVersion(db)
 
@@ -96,18 +100,27 @@ func getRawData(db dataSource) (map[string]interface{}, map[string]interface{},
 	}
 
 	replicaQuery := getReplicaQuery(dbVersion)
-	switch replication, err := db.query(replicaQuery); {
+	if isMariaDBServer(fmt.Sprint(inventory["version"])) {
+		replicaQuery = replicaQueryMariaDB
+	}
+	switch channels, err := db.queryRows(replicaQuery, ""); {
 	case err != nil:
 		log.Warn("Can't get node type, not enough privileges (must grant REPLICATION CLIENT)")
-	case len(replication) == 0:
+	case len(channels) == 0:
 		metrics["node_type"] = "master"
 	default:
 		metrics["node_type"] = "slave"
+		// The cluster.* metrics of MysqlSample describe the default channel, every channel gets its own sample.
+		replication := defaultReplicaChannel(channels)
 		for key := range replication {
 			metrics[key] = replication[key]
 		}
+		metrics[replicaChannelsKey] = channels
 	}
 
+	getGroupReplicationData(db, dbVersion, inventory, metrics)
+	getGaleraData(inventory, metrics)
+
 	metrics["key_cache_block_size"] = inventory["key_cache_block_size"]
 	metrics["key_buffer_size"] = inventory["key_buffer_size"]
 	metrics["version_comment"] = inventory["version_comment"]
@@ -151,7 +164,8 @@ func populateInventory(inventory *inventory.Inventory, rawData map[string]interf
 	}
 }
 
-func populateMetrics(sample *metric.Set, rawMetrics map[string]interface{}, dbVersion string) {
+func populateMetrics(sample *metric.Set, rawMetrics map[string]interface{}, dbVersion string, args arguments.ArgumentList) {
+	// getDefaultMetrics returns a copy, the definitions shared with the other targets are left untouched
 	defaultMetrics := getDefaultMetrics(dbVersion)
 	if rawMetrics["node_type"] != "slave" {
 		delete(defaultMetrics, "cluster.slaveRunning")
@@ -165,9 +179,19 @@ func populateMetrics(sample *metric.Set, rawMetrics map[string]interface{}, dbVe
 			for key := range slaveMetrics {
 				extendedMetrics[key] = slaveMetrics[key]
 			}
+			for key, definition := range getReplicaDelayMetrics(dbVersion, rawMetrics) {
+				extendedMetrics[key] = definition
+			}
 		}
 		populatePartialMetrics(sample, rawMetrics, extendedMetrics, dbVersion)
 	}
+	populatePartialMetrics(sample, rawMetrics, availableMetrics(getSemiSyncMetrics(rawMetrics), rawMetrics), dbVersion)
+	if _, ok := rawMetrics["group_member_state"]; ok {
+		populatePartialMetrics(sample, rawMetrics, availableMetrics(groupReplicationMetrics, rawMetrics), dbVersion)
+	}
+	if rawMetrics["wsrep_on"] == "ON" {
+		populatePartialMetrics(sample, rawMetrics, availableMetrics(galeraMetrics, rawMetrics), dbVersion)
+	}
 	if args.ExtendedInnodbMetrics {
 		populatePartialMetrics(sample, rawMetrics, innodbMetrics, dbVersion)
 	}
@@ -216,6 +240,21 @@ func populatePartialMetrics(ms *metric.Set, metrics map[string]interface{}, metr
 	}
 }
 
+// availableMetrics returns the definitions whose raw metric is present, for metrics that depend on the server
+// version or configuration and should not be reported as missing.
+func availableMetrics(metricsDefinition map[string][]interface{}, metrics map[string]interface{}) map[string][]interface{} {
+	available := make(map[string][]interface{}, len(metricsDefinition))
+	for name, definition := range metricsDefinition {
+		if source, ok := definition[0].(string); ok {
+			if _, ok := metrics[source]; !ok {
+				continue
+			}
+		}
+		available[name] = definition
+	}
+	return available
+}
+
 func isMariaDBServer(version string) bool {
 	return strings.Contains(strings.ToLower(version), "maria")
 }
@@ -119,7 +119,7 @@ func TestGetRawDataWithoutDBVersion(t *testing.T) {
 		replica: map[string]interface{}{},
 		version: map[string]interface{}{},
 	}
-	inventory, metrics, dbVersion, err := getRawData(database)
+	inventory, metrics, dbVersion, err := getRawData(database, args)
 	assert.Equal(t, "5.7.0", dbVersion)
 	if err != nil {
 		t.Error()
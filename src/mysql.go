@@ -5,6 +5,7 @@ import (
 
 	"github.com/newrelic/infra-integrations-sdk/v3/integration"
 	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
 
 	"os"
 	"runtime"
@@ -45,15 +46,83 @@ func main() {
 
 	log.SetupLogging(args.Verbose)
 
-	e, err := infrautils.CreateNodeEntity(i, args.RemoteMonitoring, args.Hostname, args.Port)
+	targets, err := args.GetTargets()
 	infrautils.FatalIfErr(err)
 
-	db, err := openSQLDB(dbutils.GenerateDSN(args, ""))
-	infrautils.FatalIfErr(err)
-	defer db.close()
+	state := newStateStore(i, args)
 
-	rawInventory, rawMetrics, dbVersion, err := getRawData(db)
-	infrautils.FatalIfErr(err)
+	results := collectTargets(targets, args.MaxConcurrentTargets, func(targetArgs arguments.ArgumentList) targetResult {
+		return collectTarget(i, targetArgs, state)
+	})
+	infrautils.FatalIfErr(i.Publish())
+
+	// A target failing the query monitoring preconditions does not stop the other targets
+	var queryMonitoringErr error
+	for _, result := range results {
+		if result.err == nil && result.args.EnableQueryMonitoring {
+			if err := queryperformancemonitoring.PopulateQueryPerformanceMetrics(result.conn, result.args, result.entity, i); err != nil {
+				log.Error("Error collecting query performance metrics of %s: %v", targetName(result.args), err)
+				queryMonitoringErr = err
+			}
+		}
+		result.close()
+	}
+
+	if err := state.Save(); err != nil {
+		log.Warn("Error saving state: %v", err)
+	}
+
+	infrautils.FatalIfErr(targetsError(results))
+	// A single instance keeps failing the execution when query monitoring can't run
+	if args.Targets == "" {
+		infrautils.FatalIfErr(queryMonitoringErr)
+	}
+}
+
+// collectTarget gathers inventory and metrics of a single MySQL instance into its own entity.
+// The availability of the instance is always reported, even when the collection fails.
+func collectTarget(i *integration.Integration, args arguments.ArgumentList, state persist.Storer) targetResult {
+	result := targetResult{args: args}
+
+	e, err := infrautils.CreateNodeEntity(i, args.RemoteMonitoring, args.Hostname, args.Port)
+	if err != nil {
+		result.err = err
+		return result
+	}
+	result.entity = e
+
+	// The connection pool stays open until query performance monitoring has run
+	var status availability
+	result.conn, result.err = dbutils.NewConnectionManager(args)
+	if result.err == nil {
+		status, result.err = collectTargetData(i, e, args, newDatabase(result.conn), state)
+		status.retries = result.conn.Retrier().Retries()
+	}
+
+	if args.HasMetrics() {
+		ms := infrautils.MetricSet(
+			e,
+			availabilitySampleName,
+			args.Hostname,
+			args.Port,
+			args.RemoteMonitoring,
+		)
+		populateAvailability(ms, status, result.err)
+	}
+	return result
+}
+
+// collectTargetData connects to the instance and populates its inventory, MysqlSample and the optional samples.
+func collectTargetData(i *integration.Integration, e *integration.Entity, args arguments.ArgumentList, db dataSource, state persist.Storer) (availability, error) {
+	status := checkAvailability(db)
+	if status.err != nil {
+		return status, status.err
+	}
+
+	rawInventory, rawMetrics, dbVersion, err := getRawData(db, args)
+	if err != nil {
+		return status, err
+	}
 
 	if args.HasInventory() {
 		populateInventory(e.Inventory, rawInventory)
@@ -67,11 +136,46 @@ func main() {
 			args.Port,
 			args.RemoteMonitoring,
 		)
-		populateMetrics(ms, rawMetrics, dbVersion)
-	}
-	infrautils.FatalIfErr(i.Publish())
+		populateMetrics(ms, rawMetrics, dbVersion, args)
+		populateReplicaChannels(e, rawMetrics, dbVersion, args)
+		if args.ReplicationApplierMetrics {
+			populateReplicationApplierMetrics(e, db, rawMetrics, dbVersion, args)
+		}
+		if args.HeartbeatLag {
+			populateHeartbeatLag(ms, db, args)
+		}
+		if args.ReplicaConnections {
+			populateReplicaConnections(e, db, rawMetrics, dbVersion, args)
+		}
+		if args.BinlogMetrics {
+			populateBinlogMetrics(ms, db, rawInventory, rawMetrics, dbVersion)
+		}
+		if args.InnodbCounterMetrics {
+			populateInnodbCounters(ms, db, args)
+		}
+		if args.InnodbStatusMetrics || args.DeadlockSamples {
+			if status, ok := queryInnodbStatus(db); ok {
+				if args.InnodbStatusMetrics {
+					populateInnodbStatus(ms, status, dbVersion)
+				}
+				if args.DeadlockSamples {
+					populateDeadlock(e, status, args, state)
+				}
+			}
+		}
 
-	if args.EnableQueryMonitoring {
-		queryperformancemonitoring.PopulateQueryPerformanceMetrics(args, e, i)
+		if args.DatabaseMetrics {
+			populateDatabaseMetrics(i, db, args)
+		}
+		if args.UserMetrics {
+			populateUserMetrics(e, db, args)
+		}
+		if args.ClientHostMetrics {
+			populateClientHostMetrics(e, db, args)
+		}
+		if args.CustomMetricsConfig != "" {
+			populateCustomQueries(e, db, args, state)
+		}
 	}
+	return status, nil
 }
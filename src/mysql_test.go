@@ -101,6 +101,19 @@ type testdb struct {
 }
 
 func (d testdb) close() {}
+func (d testdb) ping() error {
+	return nil
+}
+func (d testdb) queryRows(query string, _ string, _ ...interface{}) ([]map[string]interface{}, error) {
+	switch query {
+	case replicaQueryBelowVersion8Point4, replicaQueryForVersion8Point4AndAbove, replicaQueryMariaDB:
+		if len(d.replica) == 0 {
+			return nil, nil
+		}
+		return []map[string]interface{}{d.replica}, nil
+	}
+	return nil, nil
+}
 func (d testdb) query(query string) (map[string]interface{}, error) {
 	if query == inventoryQuery {
 		return d.inventory, nil
@@ -108,9 +121,6 @@ func (d testdb) query(query string) (map[string]interface{}, error) {
 	if query == metricsQuery {
 		return d.metrics, nil
 	}
-	if query == replicaQueryBelowVersion8Point4 {
-		return d.replica, nil
-	}
 	if query == dbVersionQuery {
 		return d.version, nil
 	}
@@ -157,7 +167,7 @@ func TestGetRawData(t *testing.T) {
 			"version": "5.6.3",
 		},
 	}
-	inventory, metrics, dbVersion, err := getRawData(database)
+	inventory, metrics, dbVersion, err := getRawData(database, args)
 	if err != nil {
 		t.Error()
 	}
@@ -170,6 +180,13 @@ func TestGetRawData(t *testing.T) {
 	if dbVersion == "" {
 		t.Error()
 	}
+	assert.Equal(t, "master", metrics["node_type"])
+
+	database.replica = map[string]interface{}{"Seconds_Behind_Master": 5, "Slave_IO_Running": "Yes"}
+	_, metrics, _, err = getRawData(database, args)
+	assert.NoError(t, err)
+	assert.Equal(t, "slave", metrics["node_type"])
+	assert.Equal(t, 5, metrics["Seconds_Behind_Master"])
 }
 
 func TestPopulateMetricsWithZeroValuesInData(t *testing.T) {
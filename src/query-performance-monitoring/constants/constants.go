@@ -5,6 +5,8 @@ import "time"
 const (
 	IntegrationName = "com.newrelic.mysql"
 	NodeEntityType  = "node"
+	// DatabaseEntityType is the type of the entities of the databases (schemas) of a node.
+	DatabaseEntityType = "database"
 	/*
 		New Relic's Integration SDK imposes a limit of 1000 metrics per ingestion.
 		To handle metric sets exceeding this limit, we process and ingest metrics in smaller chunks
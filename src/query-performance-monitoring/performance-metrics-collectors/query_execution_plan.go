@@ -11,7 +11,6 @@ import (
 	"github.com/newrelic/infra-integrations-sdk/v3/integration"
 	"github.com/newrelic/infra-integrations-sdk/v3/log"
 	arguments "github.com/newrelic/nri-mysql/src/args"
-	dbutils "github.com/newrelic/nri-mysql/src/dbutils"
 	"github.com/newrelic/nri-mysql/src/query-performance-monitoring/constants"
 	utils "github.com/newrelic/nri-mysql/src/query-performance-monitoring/utils"
 )
@@ -21,23 +20,7 @@ func PopulateExecutionPlans(db utils.DataSource, queryGroups map[string][]utils.
 	var events []utils.QueryPlanMetrics
 
 	for dbName, queries := range queryGroups {
-		dsn := dbutils.GenerateDSN(args, dbName)
-		// Open the DB connection
-		db, err := utils.OpenSQLXDB(dsn)
-		if err != nil {
-			log.Error("Error opening database connection: %v", err)
-			continue
-		}
-		defer db.Close()
-
-		for _, query := range queries {
-			tableIngestionDataList, err := processExecutionPlanMetrics(db, query, flavor)
-			if err != nil {
-				log.Error("Error processing execution plan metrics: %v", err)
-				continue
-			}
-			events = append(events, tableIngestionDataList...)
-		}
+		events = append(events, collectSchemaExecutionPlans(db, dbName, queries, flavor)...)
 	}
 
 	// Return if no metrics are collected
@@ -53,6 +36,31 @@ func PopulateExecutionPlans(db utils.DataSource, queryGroups map[string][]utils.
 	}
 }
 
+// collectSchemaExecutionPlans explains the queries of a schema on a pooled connection using it as default database,
+// so that unqualified table names are resolved as they were when the queries ran.
+func collectSchemaExecutionPlans(db utils.DataSource, dbName string, queries []utils.IndividualQueryMetrics, flavor utils.DatabaseFlavor) []utils.QueryPlanMetrics {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.QueryPlanTimeoutDuration)
+	defer cancel()
+
+	schemaDB, err := utils.UseSchema(ctx, db, dbName)
+	if err != nil {
+		log.Error("Error switching to database %s: %v", dbName, err)
+		return nil
+	}
+	defer schemaDB.Close()
+
+	var events []utils.QueryPlanMetrics
+	for _, query := range queries {
+		tableIngestionDataList, err := processExecutionPlanMetrics(schemaDB, query, flavor)
+		if err != nil {
+			log.Error("Error processing execution plan metrics: %v", err)
+			continue
+		}
+		events = append(events, tableIngestionDataList...)
+	}
+	return events
+}
+
 // processExecutionPlanMetrics processes the execution plan metrics for a given query.
 func processExecutionPlanMetrics(db utils.DataSource, query utils.IndividualQueryMetrics, flavor utils.DatabaseFlavor) ([]utils.QueryPlanMetrics, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), constants.QueryPlanTimeoutDuration)
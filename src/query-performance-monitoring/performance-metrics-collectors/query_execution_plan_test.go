@@ -15,8 +15,6 @@ import (
 	"github.com/stretchr/testify/mock"
 )
 
-var openSQLXDB func(dsn string) (*sqlx.DB, error)
-
 // Mock DataSource
 type MockDataSource struct {
 	mock.Mock
@@ -375,34 +373,11 @@ func TestIsSupportedStatement(t *testing.T) {
 }
 
 func TestPopulateExecutionPlans(t *testing.T) {
-	queryText := "SELECT * FROM test_table"
 	mockDB := new(MockDataSource)
 	mockIntegration := new(MockIntegration)
 	mockIntegration.Integration, _ = integration.New("test", "1.0.0")
 	mockArgs := arguments.ArgumentList{}
 
-	queryGroups := map[string][]utils.IndividualQueryMetrics{
-		"test_db": {
-			{QueryText: &queryText},
-		},
-	}
-
-	// Mock the OpenSQLXDB function to return the mockDB
-	openSQLXDB = func(_ string) (*sqlx.DB, error) {
-		return mockDB.db, nil
-	}
-
-	t.Run("Error Opening Database Connection", func(t *testing.T) {
-		openSQLXDB = func(_ string) (*sqlx.DB, error) {
-			return nil, assert.AnError
-		}
-
-		PopulateExecutionPlans(mockDB, queryGroups, mockIntegration.Integration, mockArgs, utils.DatabaseFlavorMySQL)
-
-		mockDB.AssertExpectations(t)
-		mockIntegration.AssertExpectations(t)
-	})
-
 	t.Run("No Metrics Collected", func(t *testing.T) {
 		queryGroups := map[string][]utils.IndividualQueryMetrics{}
 
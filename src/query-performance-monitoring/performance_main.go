@@ -7,27 +7,20 @@ import (
 	"github.com/newrelic/infra-integrations-sdk/v3/integration"
 	"github.com/newrelic/infra-integrations-sdk/v3/log"
 	arguments "github.com/newrelic/nri-mysql/src/args"
-	dbutils "github.com/newrelic/nri-mysql/src/dbutils"
-	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
 	performancemetricscollectors "github.com/newrelic/nri-mysql/src/query-performance-monitoring/performance-metrics-collectors"
 	utils "github.com/newrelic/nri-mysql/src/query-performance-monitoring/utils"
 	validator "github.com/newrelic/nri-mysql/src/query-performance-monitoring/validator"
 )
 
 // PopulateQueryPerformanceMetrics serves as the entry point for retrieving and populating query performance metrics, including slow queries, detailed query information, query execution plans, wait events, and blocking sessions.
-func PopulateQueryPerformanceMetrics(args arguments.ArgumentList, e *integration.Entity, i *integration.Integration) {
-	// Generate Data Source Name (DSN) for database connection
-	dsn := dbutils.GenerateDSN(args, "")
-
-	// Open database connection
-	db, err := utils.OpenSQLXDB(dsn)
-	infrautils.FatalIfErr(err)
-	defer db.Close()
-
+// The db connection pool is shared with the core metrics collection and remains owned by the caller.
+// An error is returned when the instance does not meet the preconditions, so that the caller decides whether the
+// other targets are still collected.
+func PopulateQueryPerformanceMetrics(db utils.DataSource, args arguments.ArgumentList, e *integration.Entity, i *integration.Integration) error {
 	// Validate preconditions before proceeding
 	profile, preValidationErr := validator.ValidatePreconditions(db)
 	if preValidationErr != nil {
-		infrautils.FatalIfErr(fmt.Errorf("preconditions failed: %w", preValidationErr))
+		return fmt.Errorf("preconditions failed: %w", preValidationErr)
 	}
 
 	querySet := utils.GetQuerySet(profile.Flavor)
@@ -74,4 +67,5 @@ func PopulateQueryPerformanceMetrics(args arguments.ArgumentList, e *integration
 	performancemetricscollectors.PopulateBlockingSessionMetrics(db, i, args, excludedDatabases, querySet)
 	log.Debug("Completed fetching blocking session metrics in %v", time.Since(start))
 	log.Debug("Query analysis completed.")
+	return nil
 }
@@ -4,8 +4,9 @@ import (
 	"context"
 	"fmt"
 
-	_ "github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	dbutils "github.com/newrelic/nri-mysql/src/dbutils"
 	constants "github.com/newrelic/nri-mysql/src/query-performance-monitoring/constants"
 )
 
@@ -15,27 +16,14 @@ type DataSource interface {
 	QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error)
 }
 
-type Database struct {
-	source *sqlx.DB
+// ConnPool is implemented by data sources backed by a connection pool, allowing a connection to be pinned
+// to run several statements in the same session.
+type ConnPool interface {
+	Connx(ctx context.Context) (*sqlx.Conn, error)
 }
 
-/*
-OpenSQLXDB function creates and returns a connection using the sqlx package for advanced query execution
-and mapping capabilities. It offers methods like Queryx, QueryRowx, etc., that facilitate working
-with structs, slices, and named queries, making it well-suited for applications needing sophisticated
-data handling compared to the standard sql package.
-*/
-func OpenSQLXDB(dsn string) (DataSource, error) {
-	source, err := sqlx.Open("mysql", dsn)
-	if err != nil {
-		return nil, fmt.Errorf("error opening DSN: %w", err)
-	}
-
-	db := Database{
-		source: source,
-	}
-
-	return &db, nil
+type Database struct {
+	source *sqlx.DB
 }
 
 func (db *Database) Close() {
@@ -52,6 +40,54 @@ func (db *Database) QueryxContext(ctx context.Context, query string, args ...int
 	return db.source.QueryxContext(ctx, query, args...)
 }
 
+// Connx pins a connection of the pool.
+func (db *Database) Connx(ctx context.Context) (*sqlx.Conn, error) {
+	return db.source.Connx(ctx)
+}
+
+// UseSchema pins a connection of db and makes schema its default database with `USE`, as required by EXPLAIN
+// for queries using unqualified table names. Closing the returned DataSource restores the previous default
+// database and releases the connection back to the pool.
+func UseSchema(ctx context.Context, db DataSource, schema string) (DataSource, error) {
+	pool, ok := db.(ConnPool)
+	if !ok {
+		return nil, ErrSchemaSwitchUnsupported
+	}
+
+	conn, err := pool.Connx(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error acquiring connection: %w", err)
+	}
+
+	restore, err := dbutils.SwitchSchema(ctx, conn.Conn, schema)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &schemaConn{conn: conn, restore: restore}, nil
+}
+
+// schemaConn is a pinned connection whose default database was changed by UseSchema.
+type schemaConn struct {
+	conn    *sqlx.Conn
+	restore func()
+}
+
+func (c *schemaConn) QueryX(query string) (*sqlx.Rows, error) {
+	return c.conn.QueryxContext(context.Background(), query)
+}
+
+func (c *schemaConn) QueryxContext(ctx context.Context, query string, args ...interface{}) (*sqlx.Rows, error) {
+	return c.conn.QueryxContext(ctx, query, args...)
+}
+
+func (c *schemaConn) Close() {
+	c.restore()
+	if err := c.conn.Close(); err != nil {
+		log.Warn("Error releasing connection: %v", err)
+	}
+}
+
 // collectMetrics collects metrics from the performance schema database
 func CollectMetrics[T any](db DataSource, preparedQuery string, preparedArgs ...interface{}) ([]T, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), constants.TimeoutDuration)
@@ -199,38 +199,6 @@ func TestDatabase_QueryxContext_Error(t *testing.T) {
 	assert.NoError(t, err)
 }
 
-func TestOpenDB(t *testing.T) {
-	tests := []struct {
-		name    string
-		dsn     string
-		wantErr bool
-	}{
-		{
-			name:    "Successful connection",
-			dsn:     "user:password@tcp(127.0.0.1:3306)/dbname",
-			wantErr: false,
-		},
-		{
-			name:    "Error opening connection",
-			dsn:     "invalid_dsn",
-			wantErr: true,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			db, err := OpenSQLXDB(tt.dsn)
-			if tt.wantErr {
-				assert.Error(t, err)
-				assert.Nil(t, db)
-			} else {
-				assert.NoError(t, err)
-				assert.NotNil(t, db)
-			}
-		})
-	}
-}
-
 func TestCollectMetrics(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -305,3 +273,43 @@ func TestCollectMetrics(t *testing.T) {
 		})
 	}
 }
+
+func TestUseSchema(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	database := &Database{source: sqlx.NewDb(db, "sqlmock")}
+
+	mock.ExpectQuery("^SELECT DATABASE\\(\\)$").WillReturnRows(sqlmock.NewRows([]string{"DATABASE()"}).AddRow("app"))
+	mock.ExpectExec("^USE `sales``db`$").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("^EXPLAIN FORMAT=JSON SELECT \\* FROM orders$").WillReturnRows(sqlmock.NewRows([]string{"EXPLAIN"}).AddRow("{}"))
+	mock.ExpectExec("^USE `app`$").WillReturnResult(sqlmock.NewResult(0, 0))
+
+	schemaDB, err := UseSchema(context.Background(), database, "sales`db")
+	assert.NoError(t, err)
+
+	rows, err := schemaDB.QueryxContext(context.Background(), "EXPLAIN FORMAT=JSON SELECT * FROM orders")
+	assert.NoError(t, err)
+	rows.Close()
+	schemaDB.Close()
+
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUseSchema_Errors(t *testing.T) {
+	_, err := UseSchema(context.Background(), new(MockDataSource), "app")
+	assert.ErrorIs(t, err, ErrSchemaSwitchUnsupported)
+
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	defer db.Close()
+
+	database := &Database{source: sqlx.NewDb(db, "sqlmock")}
+	mock.ExpectQuery("^SELECT DATABASE\\(\\)$").WillReturnRows(sqlmock.NewRows([]string{"DATABASE()"}).AddRow(nil))
+	mock.ExpectExec("^USE `missing`$").WillReturnError(errors.New("Unknown database 'missing'"))
+
+	_, err = UseSchema(context.Background(), database, "missing")
+	assert.Error(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
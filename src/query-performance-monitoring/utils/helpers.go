@@ -23,6 +23,8 @@ var (
 	ErrQueryTextNil    = errors.New("query text is nil")
 	ErrQueryTextEmpty  = errors.New("query text is empty")
 	ErrQueryIDNil      = errors.New("query ID is nil")
+
+	ErrSchemaSwitchUnsupported = errors.New("data source cannot switch schemas")
 )
 
 // AnonymizeQueryText). Priority order matters: strings are matched first so numbers inside
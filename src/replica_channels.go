@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+)
+
+const (
+	replicaChannelSampleName = "MysqlReplicaChannelSample"
+	// replicaChannelsKey holds the rows of every replication channel in the raw metrics.
+	replicaChannelsKey = "replica_channels"
+)
+
+var replicaChannelMetricsBelowVersion8Point4 = map[string][]interface{}{
+	"cluster.slaveIOState":         {"Slave_IO_State", metric.ATTRIBUTE},
+	"cluster.slaveSQLRunningState": {"Slave_SQL_Running_State", metric.ATTRIBUTE},
+	"cluster.retrievedGtidSet":     {"Retrieved_Gtid_Set", metric.ATTRIBUTE},
+	"cluster.executedGtidSet":      {"Executed_Gtid_Set", metric.ATTRIBUTE},
+}
+
+var replicaChannelMetricsForVersion8Point4AndAbove = map[string][]interface{}{
+	"cluster.slaveIOState":         {"Replica_IO_State", metric.ATTRIBUTE},
+	"cluster.slaveSQLRunningState": {"Replica_SQL_Running_State", metric.ATTRIBUTE},
+	"cluster.retrievedGtidSet":     {"Retrieved_Gtid_Set", metric.ATTRIBUTE},
+	"cluster.executedGtidSet":      {"Executed_Gtid_Set", metric.ATTRIBUTE},
+}
+
+// getReplicaChannelMetrics returns the replica metrics and the thread states reported for every channel.
+func getReplicaChannelMetrics(dbVersion string) map[string][]interface{} {
+	if isDBVersionLessThan8Point4(dbVersion) {
+		return mergeMaps(getSlaveMetrics(dbVersion), replicaChannelMetricsBelowVersion8Point4)
+	}
+	return mergeMaps(getSlaveMetrics(dbVersion), replicaChannelMetricsForVersion8Point4AndAbove)
+}
+
+// replicaChannelName returns the name of the channel of a SHOW REPLICA STATUS row, or of the connection of a
+// SHOW ALL SLAVES STATUS row on MariaDB. The default channel has an empty name.
+func replicaChannelName(channel map[string]interface{}) string {
+	for _, column := range []string{"Channel_Name", "Channel_name", "Connection_name"} {
+		if name, ok := channel[column]; ok {
+			return fmt.Sprint(name)
+		}
+	}
+	return ""
+}
+
+// defaultReplicaChannel returns the default channel of a replica, or its first channel when it only replicates
+// through named channels.
+func defaultReplicaChannel(channels []map[string]interface{}) map[string]interface{} {
+	for _, channel := range channels {
+		if replicaChannelName(channel) == "" {
+			return channel
+		}
+	}
+	return channels[0]
+}
+
+// populateReplicaChannels reports the lag, delay, thread states, errors and positions of every replication channel as
+// MysqlReplicaChannelSample of the node entity.
+func populateReplicaChannels(e *integration.Entity, rawMetrics map[string]interface{}, dbVersion string, args arguments.ArgumentList) {
+	channels, ok := rawMetrics[replicaChannelsKey].([]map[string]interface{})
+	if !ok {
+		return
+	}
+
+	channelMetrics := getReplicaChannelMetrics(dbVersion)
+	for _, channel := range channels {
+		ms := infrautils.MetricSet(
+			e,
+			replicaChannelSampleName,
+			args.Hostname,
+			args.Port,
+			args.RemoteMonitoring,
+			attribute.Attr("channelName", replicaChannelName(channel)),
+		)
+		populatePartialMetrics(ms, channel, availableMetrics(channelMetrics, channel), dbVersion)
+		populatePartialMetrics(ms, channel, getReplicaDelayMetrics(dbVersion, channel), dbVersion)
+	}
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replicaDB returns several replication channels for the replica status queries.
+type replicaDB struct {
+	testdb
+	channels  []map[string]interface{}
+	lastQuery string
+}
+
+func (d *replicaDB) queryRows(query string, _ string, _ ...interface{}) ([]map[string]interface{}, error) {
+	switch query {
+	case replicaQueryBelowVersion8Point4, replicaQueryForVersion8Point4AndAbove, replicaQueryMariaDB:
+		d.lastQuery = query
+		return d.channels, nil
+	}
+	return nil, nil
+}
+
+func newReplicaDB(version string, channels []map[string]interface{}) *replicaDB {
+	return &replicaDB{
+		testdb: testdb{
+			inventory: map[string]interface{}{"version": version},
+			metrics:   map[string]interface{}{},
+			version:   map[string]interface{}{"version": version},
+		},
+		channels: channels,
+	}
+}
+
+func TestGetRawDataUsesDefaultReplicaChannel(t *testing.T) {
+	db := newReplicaDB("8.0.36", []map[string]interface{}{
+		{"Channel_Name": "analytics", "Seconds_Behind_Master": 120},
+		{"Channel_Name": "", "Seconds_Behind_Master": 3},
+	})
+
+	_, metrics, _, err := getRawData(db, arguments.ArgumentList{})
+	require.NoError(t, err)
+
+	assert.Equal(t, replicaQueryBelowVersion8Point4, db.lastQuery)
+	assert.Equal(t, "slave", metrics["node_type"])
+	assert.Equal(t, 3, metrics["Seconds_Behind_Master"])
+	assert.Len(t, metrics[replicaChannelsKey], 2)
+}
+
+func TestGetRawDataMariaDBReplicaConnections(t *testing.T) {
+	db := newReplicaDB("10.11.6-MariaDB", []map[string]interface{}{{"Connection_name": "east", "Seconds_Behind_Master": 8}})
+
+	_, metrics, _, err := getRawData(db, arguments.ArgumentList{})
+	require.NoError(t, err)
+
+	assert.Equal(t, replicaQueryMariaDB, db.lastQuery)
+	assert.Equal(t, 8, metrics["Seconds_Behind_Master"])
+}
+
+func TestPopulateReplicaChannels(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	rawMetrics := map[string]interface{}{
+		replicaChannelsKey: []map[string]interface{}{
+			{"Channel_Name": "", "Seconds_Behind_Source": 3, "Replica_IO_Running": "Yes", "Replica_SQL_Running": "Yes",
+				"Replica_IO_State": "Waiting for source to send event", "Last_IO_Errno": 0},
+			{"Channel_Name": "analytics", "Replica_IO_Running": "Connecting", "Replica_SQL_Running": "Yes",
+				"Last_IO_Errno": 2003, "Last_IO_Error": "error connecting to source"},
+		},
+	}
+	populateReplicaChannels(e, rawMetrics, "8.4.2", arguments.ArgumentList{Port: 3306})
+
+	require.Len(t, e.Metrics, 2)
+	defaultChannel := e.Metrics[0].Metrics
+	assert.Equal(t, replicaChannelSampleName, defaultChannel["event_type"])
+	assert.Equal(t, "", defaultChannel["channelName"])
+	assert.Equal(t, 3., defaultChannel["cluster.secondsBehindMaster"])
+	assert.Equal(t, "Waiting for source to send event", defaultChannel["cluster.slaveIOState"])
+
+	analytics := e.Metrics[1].Metrics
+	assert.Equal(t, "analytics", analytics["channelName"])
+	assert.Equal(t, "Connecting", analytics["cluster.slaveIORunning"])
+	assert.Equal(t, 2003., analytics["cluster.lastIOErrno"])
+	assert.NotContains(t, analytics, "cluster.secondsBehindMaster")
+}
+
+func TestPopulateReplicaChannelsSource(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	populateReplicaChannels(e, map[string]interface{}{"node_type": "master"}, "8.4.2", arguments.ArgumentList{})
+	assert.Empty(t, e.Metrics)
+}
+
+func TestPopulateReplicaChannelsDelay(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	rawMetrics := map[string]interface{}{
+		replicaChannelsKey: []map[string]interface{}{
+			{"Channel_Name": "", "Seconds_Behind_Master": 3605, "SQL_Delay": 3600, "SQL_Remaining_Delay": 12,
+				"Retrieved_Gtid_Set": "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-120",
+				"Executed_Gtid_Set":  "3e11fa47-71ca-11e1-9e33-c80aa9429562:1-100"},
+			{"Channel_Name": "analytics", "SQL_Delay": 0, "Retrieved_Gtid_Set": "", "Executed_Gtid_Set": ""},
+		},
+	}
+	populateReplicaChannels(e, rawMetrics, "8.0.36", arguments.ArgumentList{Port: 3306})
+
+	require.Len(t, e.Metrics, 2)
+	delayed := e.Metrics[0].Metrics
+	assert.Equal(t, 3600., delayed["cluster.sqlDelaySeconds"])
+	assert.Equal(t, 12., delayed["cluster.sqlRemainingDelaySeconds"])
+	assert.Equal(t, 5., delayed["cluster.secondsBehindMasterExcludingDelay"])
+	assert.Equal(t, 20., delayed["cluster.gtidTransactionsNotExecuted"])
+
+	analytics := e.Metrics[1].Metrics
+	assert.Equal(t, 0., analytics["cluster.sqlDelaySeconds"])
+	assert.NotContains(t, analytics, "cluster.sqlRemainingDelaySeconds")
+	assert.NotContains(t, analytics, "cluster.secondsBehindMasterExcludingDelay")
+	assert.NotContains(t, analytics, "cluster.gtidTransactionsNotExecuted")
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+)
+
+const (
+	replicaConnectionSampleName     = "MysqlReplicaConnectionSample"
+	replicaHostsBelowVersion8Point4 = "SHOW SLAVE HOSTS"
+	// From MySQL 8.4 SHOW SLAVE HOSTS is removed and SHOW REPLICAS should be used instead
+	replicaHostsForVersion8Point4AndAbove = "SHOW REPLICAS"
+)
+
+// binlogDumpThreadsQuery returns the threads sending the binary log to connected replicas.
+const binlogDumpThreadsQuery = `SELECT PROCESSLIST_ID AS thread_id, PROCESSLIST_USER AS user, PROCESSLIST_HOST AS host,
+	PROCESSLIST_COMMAND AS command, PROCESSLIST_STATE AS state, PROCESSLIST_TIME AS time
+	FROM performance_schema.threads
+	WHERE PROCESSLIST_COMMAND IN ('Binlog Dump', 'Binlog Dump GTID')`
+
+var replicaConnectionMetrics = map[string][]interface{}{
+	"replica.serverId":          {"server_id", metric.ATTRIBUTE},
+	"replica.serverUuid":        {"server_uuid", metric.ATTRIBUTE},
+	"replica.host":              {"host", metric.ATTRIBUTE},
+	"replica.port":              {"port", metric.ATTRIBUTE},
+	"replica.entityName":        {"entity_name", metric.ATTRIBUTE},
+	"replica.dumpThreadId":      {"thread_id", metric.ATTRIBUTE},
+	"replica.dumpThreadUser":    {"user", metric.ATTRIBUTE},
+	"replica.dumpThreadCommand": {"command", metric.ATTRIBUTE},
+	"replica.dumpThreadState":   {"state", metric.ATTRIBUTE},
+	"replica.dumpThreadSeconds": {"time", metric.GAUGE},
+}
+
+// replicaHostColumns maps the columns of SHOW REPLICAS and of the legacy SHOW SLAVE HOSTS to raw metric names.
+var replicaHostColumns = map[string]string{
+	"Server_Id":    "server_id",
+	"Server_id":    "server_id",
+	"Host":         "host",
+	"Port":         "port",
+	"Replica_UUID": "server_uuid",
+	"Slave_UUID":   "server_uuid",
+}
+
+// getReplicaHostsQuery returns the query listing the registered replicas. MariaDB only supports SHOW SLAVE HOSTS.
+func getReplicaHostsQuery(dbVersion string, version string) string {
+	if isMariaDBServer(version) || isDBVersionLessThan8Point4(dbVersion) {
+		return replicaHostsBelowVersion8Point4
+	}
+	return replicaHostsForVersion8Point4AndAbove
+}
+
+/*
+populateReplicaConnections reports the replicas connected to the source as MysqlReplicaConnectionSample of the node
+entity. The replica.entityName attribute holds the entity name of the replica and, when the source is monitored
+remotely, source.entityName holds the name of the node entity, to relate the source to its replicas. Local entities
+have no name, so source.entityName is left out for them.
+
+Replicas registered with report_host are matched to their binlog dump thread by host, which requires report_host to
+be the address the replica connects from: a replica reporting a hostname while connecting from its IP address gets
+a separate sample for its dump thread. The client port of a dump thread is not the port reported by the replica, so
+the dump threads of several replicas running on the same host are assigned to them in order.
+*/
+func populateReplicaConnections(e *integration.Entity, db dataSource, rawMetrics map[string]interface{}, dbVersion string, args arguments.ArgumentList) {
+	connections := getReplicaConnections(db, fmt.Sprint(rawMetrics["version"]), dbVersion)
+
+	var attributes []attribute.Attribute
+	if e.Metadata != nil {
+		attributes = append(attributes, attribute.Attr("source.entityName", e.Metadata.Name))
+	}
+	for _, connection := range connections {
+		ms := infrautils.MetricSet(
+			e,
+			replicaConnectionSampleName,
+			args.Hostname,
+			args.Port,
+			args.RemoteMonitoring,
+			attributes...,
+		)
+		populatePartialMetrics(ms, connection, availableMetrics(replicaConnectionMetrics, connection), dbVersion)
+	}
+}
+
+// getReplicaConnections merges the replicas registered on the source with the binlog dump threads serving them.
+func getReplicaConnections(db dataSource, version string, dbVersion string) []map[string]interface{} {
+	hosts, err := db.queryRows(getReplicaHostsQuery(dbVersion, version), "")
+	if err != nil {
+		log.Warn("Can't get registered replicas (must grant REPLICATION SLAVE): %v", err)
+	}
+	threads, err := db.queryRows(binlogDumpThreadsQuery, "")
+	if err != nil {
+		log.Warn("Can't get binlog dump threads (performance_schema may not be enabled): %v", err)
+	}
+
+	connections := make([]map[string]interface{}, 0, len(hosts)+len(threads))
+	registered := map[string][]map[string]interface{}{}
+	for _, host := range hosts {
+		connection := map[string]interface{}{}
+		for column, value := range host {
+			if name, ok := replicaHostColumns[column]; ok {
+				connection[name] = fmt.Sprint(value)
+			}
+		}
+		if host, ok := connection["host"].(string); ok && host != "" {
+			connection["entity_name"] = fmt.Sprint(host, ":", connection["port"])
+			registered[host] = append(registered[host], connection)
+		}
+		connections = append(connections, connection)
+	}
+
+	for _, thread := range threads {
+		host := dumpThreadHost(fmt.Sprint(thread["host"]))
+		connection := unmatchedReplica(registered[host])
+		if connection == nil {
+			connection = map[string]interface{}{"host": host}
+			connections = append(connections, connection)
+		}
+		for _, key := range []string{"thread_id", "user", "command", "state"} {
+			if value, ok := thread[key]; ok {
+				connection[key] = fmt.Sprint(value)
+			}
+		}
+		if value, ok := thread["time"]; ok {
+			connection["time"] = value
+		}
+	}
+	return connections
+}
+
+// unmatchedReplica returns the first of the replicas registered on a host that has no binlog dump thread yet.
+func unmatchedReplica(replicas []map[string]interface{}) map[string]interface{} {
+	for _, replica := range replicas {
+		if _, ok := replica["thread_id"]; !ok {
+			return replica
+		}
+	}
+	return nil
+}
+
+// dumpThreadHost strips the client port from the host of a binlog dump thread.
+func dumpThreadHost(host string) string {
+	if name, _, err := net.SplitHostPort(host); err == nil {
+		return name
+	}
+	return host
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sourceDB returns the registered replicas and the binlog dump threads of a source.
+type sourceDB struct {
+	testdb
+	hosts     []map[string]interface{}
+	threads   []map[string]interface{}
+	hostQuery string
+}
+
+func (d *sourceDB) queryRows(query string, _ string, _ ...interface{}) ([]map[string]interface{}, error) {
+	if query == binlogDumpThreadsQuery {
+		return d.threads, nil
+	}
+	d.hostQuery = query
+	return d.hosts, nil
+}
+
+func TestGetReplicaConnections(t *testing.T) {
+	db := &sourceDB{
+		hosts: []map[string]interface{}{
+			{"Server_Id": 2, "Host": "10.0.0.2", "Port": 3306, "Source_Id": 1, "Replica_UUID": "uuid-2"},
+			{"Server_Id": 3, "Host": "", "Port": 3306, "Source_Id": 1, "Replica_UUID": "uuid-3"},
+		},
+		threads: []map[string]interface{}{
+			{"thread_id": 31, "user": "repl", "host": "10.0.0.2:51234", "command": "Binlog Dump GTID",
+				"state": "Source has sent all binlog to replica; waiting for more updates", "time": 3600},
+			{"thread_id": 32, "user": "repl", "host": "10.0.0.3:40022", "command": "Binlog Dump", "time": 60},
+		},
+	}
+
+	connections := getReplicaConnections(db, "8.4.2", "8.4.2")
+
+	assert.Equal(t, replicaHostsForVersion8Point4AndAbove, db.hostQuery)
+	require.Len(t, connections, 3)
+	assert.Equal(t, map[string]interface{}{
+		"server_id": "2", "host": "10.0.0.2", "port": "3306", "server_uuid": "uuid-2", "entity_name": "10.0.0.2:3306",
+		"thread_id": "31", "user": "repl", "command": "Binlog Dump GTID",
+		"state": "Source has sent all binlog to replica; waiting for more updates", "time": 3600,
+	}, connections[0])
+	assert.Equal(t, "uuid-3", connections[1]["server_uuid"])
+	assert.NotContains(t, connections[1], "thread_id")
+	assert.Equal(t, "10.0.0.3", connections[2]["host"])
+	assert.Equal(t, "32", connections[2]["thread_id"])
+}
+
+func TestPopulateReplicaConnectionsLegacyTerms(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	db := &sourceDB{hosts: []map[string]interface{}{
+		{"Server_id": 2, "Host": "replica-1", "Port": 3307, "Master_id": 1, "Slave_UUID": "uuid-2"},
+	}}
+	populateReplicaConnections(e, db, map[string]interface{}{"version": "5.7.44"}, "5.7.44", arguments.ArgumentList{Hostname: "source-1", Port: 3306})
+
+	assert.Equal(t, replicaHostsBelowVersion8Point4, db.hostQuery)
+	require.Len(t, e.Metrics, 1)
+	sample := e.Metrics[0].Metrics
+	assert.Equal(t, replicaConnectionSampleName, sample["event_type"])
+	assert.Equal(t, "replica-1:3307", sample["replica.entityName"])
+	assert.Equal(t, "uuid-2", sample["replica.serverUuid"])
+	// The local entity has no name to relate the replicas to
+	assert.NotContains(t, sample, "source.entityName")
+}
+
+func TestPopulateReplicaConnectionsRemoteSource(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	args := arguments.ArgumentList{Hostname: "source-1", Port: 3306, RemoteMonitoring: true}
+	e, err := infrautils.CreateNodeEntity(i, args.RemoteMonitoring, args.Hostname, args.Port)
+	require.NoError(t, err)
+
+	db := &sourceDB{hosts: []map[string]interface{}{{"Server_Id": 2, "Host": "replica-1", "Port": 3306}}}
+	populateReplicaConnections(e, db, map[string]interface{}{"version": "8.4.2"}, "8.4.2", args)
+
+	require.Len(t, e.Metrics, 1)
+	assert.Equal(t, e.Metadata.Name, e.Metrics[0].Metrics["source.entityName"])
+	assert.Equal(t, "source-1:3306", e.Metrics[0].Metrics["source.entityName"])
+}
+
+func TestGetReplicaConnectionsMariaDB(t *testing.T) {
+	db := &sourceDB{hosts: []map[string]interface{}{{"Server_id": 2, "Host": "replica-1", "Port": 3306, "Master_id": 1}}}
+
+	connections := getReplicaConnections(db, "11.4.2-MariaDB", "11.4.2")
+
+	assert.Equal(t, replicaHostsBelowVersion8Point4, db.hostQuery)
+	require.Len(t, connections, 1)
+	assert.Equal(t, "replica-1:3306", connections[0]["entity_name"])
+}
+
+func TestGetReplicaConnectionsSeveralReplicasOnHost(t *testing.T) {
+	db := &sourceDB{
+		hosts: []map[string]interface{}{
+			{"Server_Id": 2, "Host": "10.0.0.2", "Port": 3306},
+			{"Server_Id": 3, "Host": "10.0.0.2", "Port": 3307},
+		},
+		threads: []map[string]interface{}{
+			{"thread_id": 31, "host": "10.0.0.2:51234", "command": "Binlog Dump GTID"},
+			{"thread_id": 32, "host": "10.0.0.2:51240", "command": "Binlog Dump GTID"},
+		},
+	}
+
+	connections := getReplicaConnections(db, "8.4.2", "8.4.2")
+
+	require.Len(t, connections, 2)
+	assert.Equal(t, "10.0.0.2:3306", connections[0]["entity_name"])
+	assert.Equal(t, "31", connections[0]["thread_id"])
+	assert.Equal(t, "10.0.0.2:3307", connections[1]["entity_name"])
+	assert.Equal(t, "32", connections[1]["thread_id"])
+}
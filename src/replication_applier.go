@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+)
+
+const (
+	replicaApplierWorkerSampleName      = "MysqlReplicaApplierWorkerSample"
+	replicaApplierCoordinatorSampleName = "MysqlReplicaApplierCoordinatorSample"
+	replicaConnectionStatusSampleName   = "MysqlReplicaConnectionStatusSample"
+)
+
+/*
+The lag of the transactions being applied or queued is measured from their immediate commit timestamp, the time
+they were committed on the immediate source, which is accurate on multi-threaded replicas unlike
+Seconds_Behind_Source. Idle threads report no lag, and lags of threads that never handled a transaction are NULL.
+*/
+const replicaApplierWorkerQuery = `SELECT CHANNEL_NAME AS channel_name, WORKER_ID AS worker_id,
+	SERVICE_STATE AS service_state, LAST_ERROR_NUMBER AS last_error_number, LAST_ERROR_MESSAGE AS last_error_message,
+	LAST_APPLIED_TRANSACTION AS last_applied_transaction, APPLYING_TRANSACTION AS applying_transaction,
+	IF(APPLYING_TRANSACTION = '', 0,
+		TIMESTAMPDIFF(MICROSECOND, APPLYING_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP, NOW(6)) / 1000000) AS applying_lag,
+	IF(LAST_APPLIED_TRANSACTION = '', NULL,
+		TIMESTAMPDIFF(MICROSECOND, LAST_APPLIED_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP,
+			LAST_APPLIED_TRANSACTION_END_APPLY_TIMESTAMP) / 1000000) AS last_applied_lag,
+	IF(LAST_APPLIED_TRANSACTION = '', NULL,
+		TIMESTAMPDIFF(MICROSECOND, LAST_APPLIED_TRANSACTION_START_APPLY_TIMESTAMP,
+			LAST_APPLIED_TRANSACTION_END_APPLY_TIMESTAMP) / 1000000) AS last_applied_duration
+	FROM performance_schema.replication_applier_status_by_worker`
+
+const replicaApplierCoordinatorQuery = `SELECT CHANNEL_NAME AS channel_name, SERVICE_STATE AS service_state,
+	LAST_ERROR_NUMBER AS last_error_number, LAST_ERROR_MESSAGE AS last_error_message,
+	LAST_PROCESSED_TRANSACTION AS last_processed_transaction, PROCESSING_TRANSACTION AS processing_transaction,
+	IF(PROCESSING_TRANSACTION = '', 0,
+		TIMESTAMPDIFF(MICROSECOND, PROCESSING_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP, NOW(6)) / 1000000) AS processing_lag,
+	IF(LAST_PROCESSED_TRANSACTION = '', NULL,
+		TIMESTAMPDIFF(MICROSECOND, LAST_PROCESSED_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP,
+			LAST_PROCESSED_TRANSACTION_END_BUFFER_TIMESTAMP) / 1000000) AS last_processed_lag
+	FROM performance_schema.replication_applier_status_by_coordinator`
+
+const replicaConnectionStatusQuery = `SELECT CHANNEL_NAME AS channel_name, SERVICE_STATE AS service_state,
+	SOURCE_UUID AS source_uuid, LAST_ERROR_NUMBER AS last_error_number, LAST_ERROR_MESSAGE AS last_error_message,
+	COUNT_RECEIVED_HEARTBEATS AS received_heartbeats,
+	IF(COUNT_RECEIVED_HEARTBEATS = 0, NULL,
+		TIMESTAMPDIFF(MICROSECOND, LAST_HEARTBEAT_TIMESTAMP, NOW(6)) / 1000000) AS last_heartbeat_age,
+	LAST_QUEUED_TRANSACTION AS last_queued_transaction, QUEUEING_TRANSACTION AS queueing_transaction,
+	IF(QUEUEING_TRANSACTION = '', 0,
+		TIMESTAMPDIFF(MICROSECOND, QUEUEING_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP, NOW(6)) / 1000000) AS queueing_lag,
+	IF(LAST_QUEUED_TRANSACTION = '', NULL,
+		TIMESTAMPDIFF(MICROSECOND, LAST_QUEUED_TRANSACTION_IMMEDIATE_COMMIT_TIMESTAMP,
+			LAST_QUEUED_TRANSACTION_END_QUEUE_TIMESTAMP) / 1000000) AS last_queued_lag
+	FROM performance_schema.replication_connection_status`
+
+var replicaApplierWorkerMetrics = map[string][]interface{}{
+	"replica.serviceState":           {"service_state", metric.ATTRIBUTE},
+	"replica.lastErrorNumber":        {"last_error_number", metric.GAUGE},
+	"replica.lastErrorMessage":       {"last_error_message", metric.ATTRIBUTE},
+	"replica.lastAppliedTransaction": {"last_applied_transaction", metric.ATTRIBUTE},
+	"replica.applyingTransaction":    {"applying_transaction", metric.ATTRIBUTE},
+	"replica.applyingLagSeconds":     {"applying_lag", metric.GAUGE},
+	"replica.lastAppliedLagSeconds":  {"last_applied_lag", metric.GAUGE},
+	"replica.lastAppliedSeconds":     {"last_applied_duration", metric.GAUGE},
+}
+
+var replicaApplierCoordinatorMetrics = map[string][]interface{}{
+	"replica.serviceState":             {"service_state", metric.ATTRIBUTE},
+	"replica.lastErrorNumber":          {"last_error_number", metric.GAUGE},
+	"replica.lastErrorMessage":         {"last_error_message", metric.ATTRIBUTE},
+	"replica.lastProcessedTransaction": {"last_processed_transaction", metric.ATTRIBUTE},
+	"replica.processingTransaction":    {"processing_transaction", metric.ATTRIBUTE},
+	"replica.processingLagSeconds":     {"processing_lag", metric.GAUGE},
+	"replica.lastProcessedLagSeconds":  {"last_processed_lag", metric.GAUGE},
+}
+
+var replicaConnectionStatusMetrics = map[string][]interface{}{
+	"replica.serviceState":                {"service_state", metric.ATTRIBUTE},
+	"replica.sourceUuid":                  {"source_uuid", metric.ATTRIBUTE},
+	"replica.lastErrorNumber":             {"last_error_number", metric.GAUGE},
+	"replica.lastErrorMessage":            {"last_error_message", metric.ATTRIBUTE},
+	"replica.receivedHeartbeatsPerSecond": {"received_heartbeats", metric.PRATE},
+	"replica.lastHeartbeatAgeSeconds":     {"last_heartbeat_age", metric.GAUGE},
+	"replica.lastQueuedTransaction":       {"last_queued_transaction", metric.ATTRIBUTE},
+	"replica.queueingTransaction":         {"queueing_transaction", metric.ATTRIBUTE},
+	"replica.queueingLagSeconds":          {"queueing_lag", metric.GAUGE},
+	"replica.lastQueuedLagSeconds":        {"last_queued_lag", metric.GAUGE},
+}
+
+/*
+populateReplicationApplierMetrics reports the state, errors, transactions and lag of every applier worker, applier
+coordinator and receiver connection of the replication channels from performance_schema. The timestamps these
+metrics rely on were added in MySQL 8.0, so older servers and MariaDB are skipped.
+*/
+func populateReplicationApplierMetrics(e *integration.Entity, db dataSource, rawMetrics map[string]interface{}, dbVersion string, args arguments.ArgumentList) {
+	if _, ok := rawMetrics[replicaChannelsKey]; !ok {
+		return
+	}
+	if isMariaDBServer(fmt.Sprint(rawMetrics["version"])) || isDBVersionLessThan8(dbVersion) {
+		log.Debug("Replication applier metrics require MySQL 8.0 or later")
+		return
+	}
+
+	populateReplicationStatus(e, db, replicaApplierWorkerQuery, replicaApplierWorkerSampleName, replicaApplierWorkerMetrics, dbVersion, args)
+	populateReplicationStatus(e, db, replicaApplierCoordinatorQuery, replicaApplierCoordinatorSampleName, replicaApplierCoordinatorMetrics, dbVersion, args)
+	populateReplicationStatus(e, db, replicaConnectionStatusQuery, replicaConnectionStatusSampleName, replicaConnectionStatusMetrics, dbVersion, args)
+}
+
+// populateReplicationStatus reports a sample per row of a replication status table, identified by the channel and,
+// for applier workers, the worker id.
+func populateReplicationStatus(e *integration.Entity, db dataSource, query string, sampleName string,
+	definitions map[string][]interface{}, dbVersion string, args arguments.ArgumentList) {
+	rows, err := db.queryRows(query, "")
+	if err != nil {
+		log.Warn("Can't get %s (performance_schema may not be enabled): %v", sampleName, err)
+		return
+	}
+
+	for _, row := range rows {
+		attributes := []attribute.Attribute{attribute.Attr("channelName", fmt.Sprint(row["channel_name"]))}
+		if worker, ok := row["worker_id"]; ok {
+			attributes = append(attributes, attribute.Attr("workerId", fmt.Sprint(worker)))
+		}
+
+		ms := infrautils.MetricSet(
+			e,
+			sampleName,
+			args.Hostname,
+			args.Port,
+			args.RemoteMonitoring,
+			attributes...,
+		)
+		populatePartialMetrics(ms, row, availableMetrics(definitions, row), dbVersion)
+	}
+}
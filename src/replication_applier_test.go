@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// replicationStatusDB returns the rows of the replication status tables by query.
+type replicationStatusDB struct {
+	testdb
+	rows    map[string][]map[string]interface{}
+	queries []string
+}
+
+func (d *replicationStatusDB) queryRows(query string, _ string, _ ...interface{}) ([]map[string]interface{}, error) {
+	d.queries = append(d.queries, query)
+	return d.rows[query], nil
+}
+
+func TestPopulateReplicationApplierMetrics(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	db := &replicationStatusDB{rows: map[string][]map[string]interface{}{
+		replicaApplierWorkerQuery: {
+			{"channel_name": "", "worker_id": 1, "service_state": "ON", "last_error_number": 0, "last_error_message": "",
+				"last_applied_transaction": "3e11fa47-71ca-11e1-9e33-c80aa9429562:23", "applying_transaction": "",
+				"applying_lag": 0, "last_applied_lag": 0.25, "last_applied_duration": 0.01},
+			{"channel_name": "", "worker_id": 2, "service_state": "OFF", "last_error_number": 1062,
+				"last_error_message": "Duplicate entry '1' for key 'PRIMARY'", "applying_transaction": "",
+				"applying_lag": 0},
+		},
+		replicaApplierCoordinatorQuery: {
+			{"channel_name": "", "service_state": "ON", "last_error_number": 0, "processing_transaction": "",
+				"processing_lag": 0},
+		},
+		replicaConnectionStatusQuery: {
+			{"channel_name": "", "service_state": "ON", "source_uuid": "3e11fa47-71ca-11e1-9e33-c80aa9429562",
+				"last_error_number": 0, "received_heartbeats": 10, "queueing_transaction": "", "queueing_lag": 0,
+				"last_queued_lag": 0.002},
+		},
+	}}
+	rawMetrics := map[string]interface{}{replicaChannelsKey: []map[string]interface{}{{}}}
+	populateReplicationApplierMetrics(e, db, rawMetrics, "8.0.36", arguments.ArgumentList{Port: 3306})
+
+	require.Len(t, e.Metrics, 4)
+	worker := e.Metrics[0].Metrics
+	assert.Equal(t, replicaApplierWorkerSampleName, worker["event_type"])
+	assert.Equal(t, "", worker["channelName"])
+	assert.Equal(t, "1", worker["workerId"])
+	assert.Equal(t, "ON", worker["replica.serviceState"])
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562:23", worker["replica.lastAppliedTransaction"])
+	assert.Equal(t, 0., worker["replica.applyingLagSeconds"])
+	assert.Equal(t, 0.25, worker["replica.lastAppliedLagSeconds"])
+
+	stopped := e.Metrics[1].Metrics
+	assert.Equal(t, "2", stopped["workerId"])
+	assert.Equal(t, 1062., stopped["replica.lastErrorNumber"])
+	assert.Equal(t, "Duplicate entry '1' for key 'PRIMARY'", stopped["replica.lastErrorMessage"])
+	assert.NotContains(t, stopped, "replica.lastAppliedLagSeconds")
+
+	coordinator := e.Metrics[2].Metrics
+	assert.Equal(t, replicaApplierCoordinatorSampleName, coordinator["event_type"])
+	assert.NotContains(t, coordinator, "workerId")
+	assert.Equal(t, 0., coordinator["replica.processingLagSeconds"])
+
+	connection := e.Metrics[3].Metrics
+	assert.Equal(t, replicaConnectionStatusSampleName, connection["event_type"])
+	assert.Equal(t, "3e11fa47-71ca-11e1-9e33-c80aa9429562", connection["replica.sourceUuid"])
+	assert.Equal(t, 0.002, connection["replica.lastQueuedLagSeconds"])
+}
+
+func TestPopulateReplicationApplierMetricsSkipped(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+	rawMetrics := map[string]interface{}{replicaChannelsKey: []map[string]interface{}{{}}}
+
+	db := &replicationStatusDB{}
+	populateReplicationApplierMetrics(e, db, map[string]interface{}{}, "8.0.36", arguments.ArgumentList{Port: 3306})
+	assert.Empty(t, db.queries)
+
+	populateReplicationApplierMetrics(e, db, rawMetrics, "5.7.44", arguments.ArgumentList{Port: 3306})
+	assert.Empty(t, db.queries)
+
+	rawMetrics["version"] = "11.4.2-MariaDB"
+	populateReplicationApplierMetrics(e, db, rawMetrics, "11.4.2", arguments.ArgumentList{Port: 3306})
+	assert.Empty(t, db.queries)
+	assert.Empty(t, e.Metrics)
+}
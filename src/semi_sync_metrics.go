@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+)
+
+// semiSyncMetricsLegacy are reported by the rpl_semi_sync_master and rpl_semi_sync_slave plugins, and by MariaDB.
+var semiSyncMetricsLegacy = map[string][]interface{}{
+	"cluster.semiSyncSourceStatus":            {"Rpl_semi_sync_master_status", metric.ATTRIBUTE},
+	"cluster.semiSyncReplicaStatus":           {"Rpl_semi_sync_slave_status", metric.ATTRIBUTE},
+	"cluster.semiSyncClients":                 {"Rpl_semi_sync_master_clients", metric.GAUGE},
+	"cluster.semiSyncWaitSessions":            {"Rpl_semi_sync_master_wait_sessions", metric.GAUGE},
+	"cluster.semiSyncNoTxPerSecond":           {"Rpl_semi_sync_master_no_tx", metric.PRATE},
+	"cluster.semiSyncYesTxPerSecond":          {"Rpl_semi_sync_master_yes_tx", metric.PRATE},
+	"cluster.semiSyncTxAvgWaitTimeMicros":     {"Rpl_semi_sync_master_tx_avg_wait_time", metric.GAUGE},
+	"cluster.semiSyncAsyncFallbacksPerSecond": {"Rpl_semi_sync_master_no_times", metric.PRATE},
+}
+
+// semiSyncMetricsSourceReplica are reported by the rpl_semi_sync_source and rpl_semi_sync_replica plugins, which use
+// the Source, Replica terms from MySQL 8.0.26. The legacy plugins are removed in MySQL 8.4.
+var semiSyncMetricsSourceReplica = map[string][]interface{}{
+	"cluster.semiSyncSourceStatus":            {"Rpl_semi_sync_source_status", metric.ATTRIBUTE},
+	"cluster.semiSyncReplicaStatus":           {"Rpl_semi_sync_replica_status", metric.ATTRIBUTE},
+	"cluster.semiSyncClients":                 {"Rpl_semi_sync_source_clients", metric.GAUGE},
+	"cluster.semiSyncWaitSessions":            {"Rpl_semi_sync_source_wait_sessions", metric.GAUGE},
+	"cluster.semiSyncNoTxPerSecond":           {"Rpl_semi_sync_source_no_tx", metric.PRATE},
+	"cluster.semiSyncYesTxPerSecond":          {"Rpl_semi_sync_source_yes_tx", metric.PRATE},
+	"cluster.semiSyncTxAvgWaitTimeMicros":     {"Rpl_semi_sync_source_tx_avg_wait_time", metric.GAUGE},
+	"cluster.semiSyncAsyncFallbacksPerSecond": {"Rpl_semi_sync_source_no_times", metric.PRATE},
+}
+
+/*
+getSemiSyncMetrics returns the semi-synchronous replication metrics of the plugins reporting status variables. Between
+MySQL 8.0.26 and 8.4 either plugin may be installed and MariaDB only reports the legacy names, so the names are
+chosen from the status variables present rather than from the version.
+*/
+func getSemiSyncMetrics(rawMetrics map[string]interface{}) map[string][]interface{} {
+	_, legacySource := rawMetrics["Rpl_semi_sync_master_status"]
+	_, legacyReplica := rawMetrics["Rpl_semi_sync_slave_status"]
+	if legacySource || legacyReplica {
+		return semiSyncMetricsLegacy
+	}
+	return semiSyncMetricsSourceReplica
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSemiSyncMetrics(t *testing.T) {
+	assert.Equal(t, semiSyncMetricsSourceReplica, getSemiSyncMetrics(map[string]interface{}{}))
+	assert.Equal(t, semiSyncMetricsLegacy, getSemiSyncMetrics(map[string]interface{}{"Rpl_semi_sync_master_status": "ON"}))
+	assert.Equal(t, semiSyncMetricsLegacy, getSemiSyncMetrics(map[string]interface{}{"Rpl_semi_sync_slave_status": "ON"}))
+	assert.Equal(t, semiSyncMetricsSourceReplica, getSemiSyncMetrics(map[string]interface{}{"Rpl_semi_sync_source_status": "ON"}))
+}
+
+func TestPopulateMetricsSemiSync(t *testing.T) {
+	rawMetrics := map[string]interface{}{
+		"node_type":                             "master",
+		"Rpl_semi_sync_source_status":           "ON",
+		"Rpl_semi_sync_source_clients":          2,
+		"Rpl_semi_sync_source_yes_tx":           1200,
+		"Rpl_semi_sync_source_no_tx":            3,
+		"Rpl_semi_sync_source_tx_avg_wait_time": 850,
+		"Rpl_semi_sync_source_no_times":         1,
+	}
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateMetrics(ms, rawMetrics, "8.4.2", arguments.ArgumentList{})
+
+	assert.Equal(t, "ON", ms.Metrics["cluster.semiSyncSourceStatus"])
+	assert.Equal(t, 2., ms.Metrics["cluster.semiSyncClients"])
+	assert.Equal(t, 850., ms.Metrics["cluster.semiSyncTxAvgWaitTimeMicros"])
+	assert.Contains(t, ms.Metrics, "cluster.semiSyncYesTxPerSecond")
+	assert.Contains(t, ms.Metrics, "cluster.semiSyncAsyncFallbacksPerSecond")
+	assert.NotContains(t, ms.Metrics, "cluster.semiSyncReplicaStatus")
+}
+
+func TestPopulateMetricsSemiSyncMariaDB(t *testing.T) {
+	rawMetrics := map[string]interface{}{
+		"node_type":                             "master",
+		"version":                               "11.4.2-MariaDB",
+		"Rpl_semi_sync_master_status":           "ON",
+		"Rpl_semi_sync_master_clients":          1,
+		"Rpl_semi_sync_master_yes_tx":           40,
+		"Rpl_semi_sync_master_no_tx":            0,
+		"Rpl_semi_sync_master_tx_avg_wait_time": 420,
+		"Rpl_semi_sync_slave_status":            "OFF",
+	}
+	ms := metric.NewSet("MysqlSample", persist.NewInMemoryStore(), attribute.Attr("hostname", "localhost"))
+	populateMetrics(ms, rawMetrics, "11.4.2", arguments.ArgumentList{})
+
+	assert.Equal(t, "ON", ms.Metrics["cluster.semiSyncSourceStatus"])
+	assert.Equal(t, "OFF", ms.Metrics["cluster.semiSyncReplicaStatus"])
+	assert.Equal(t, 1., ms.Metrics["cluster.semiSyncClients"])
+	assert.Equal(t, 420., ms.Metrics["cluster.semiSyncTxAvgWaitTimeMicros"])
+}
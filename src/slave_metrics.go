@@ -1,6 +1,10 @@
 package main
 
 import (
+	"fmt"
+	"math"
+	"strconv"
+
 	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
 )
 
@@ -35,12 +39,68 @@ var slaveMetricsForVersion8Point4AndAbove = map[string][]interface{}{
 	"cluster.masterHost":          {"Source_Host", metric.ATTRIBUTE},
 }
 
-// mergeMaps merges two maps, overwriting map1 with any conflicting keys from map2.
+// replicaDelayMetrics describe the delay configured with SOURCE_DELAY (MASTER_DELAY) and, while the applier waits
+// for it, the time left before the next event is applied.
+var replicaDelayMetrics = map[string][]interface{}{
+	"cluster.sqlDelaySeconds":          {"SQL_Delay", metric.GAUGE},
+	"cluster.sqlRemainingDelaySeconds": {"SQL_Remaining_Delay", metric.GAUGE},
+}
+
+/*
+getReplicaDelayMetrics returns the delay metrics available for a replication channel. The lag of delayed replicas is
+also reported without the configured delay, so that it can be alerted on like the lag of other replicas, and GTID
+replicas report the number of transactions received but not applied yet.
+*/
+func getReplicaDelayMetrics(dbVersion string, replication map[string]interface{}) map[string][]interface{} {
+	delayMetrics := availableMetrics(replicaDelayMetrics, replication)
+
+	lagColumn := "Seconds_Behind_Source"
+	if isDBVersionLessThan8Point4(dbVersion) {
+		lagColumn = "Seconds_Behind_Master"
+	}
+	_, hasLag := replication[lagColumn]
+	_, hasDelay := replication["SQL_Delay"]
+	if hasLag && hasDelay {
+		delayMetrics["cluster.secondsBehindMasterExcludingDelay"] = []interface{}{
+			func(metrics map[string]interface{}) (float64, bool) {
+				return lagExcludingDelay(metrics, lagColumn)
+			},
+			metric.GAUGE,
+		}
+	}
+
+	// Executed_Gtid_Set is empty when gtid_mode is OFF and missing on MariaDB
+	_, hasRetrieved := replication["Retrieved_Gtid_Set"]
+	if executed, ok := replication["Executed_Gtid_Set"]; ok && hasRetrieved && fmt.Sprint(executed) != "" {
+		delayMetrics["cluster.gtidTransactionsNotExecuted"] = []interface{}{gtidTransactionsNotExecuted, metric.GAUGE}
+	}
+	return delayMetrics
+}
+
+// lagExcludingDelay returns the replication lag minus the delay configured for the channel, never below zero.
+func lagExcludingDelay(metrics map[string]interface{}, lagColumn string) (float64, bool) {
+	lag, err := strconv.ParseFloat(fmt.Sprint(metrics[lagColumn]), 64)
+	if err != nil {
+		return 0, false
+	}
+	delay, err := strconv.ParseFloat(fmt.Sprint(metrics["SQL_Delay"]), 64)
+	if err != nil {
+		return 0, false
+	}
+	return math.Max(lag-delay, 0), true
+}
+
+// mergeMaps returns a new map with the keys of both maps, map2 overwriting any conflicting keys of map1.
+// The package level definitions are shared by every target collected concurrently, so they are never modified.
 func mergeMaps(map1, map2 map[string][]interface{}) map[string][]interface{} {
+	merged := make(map[string][]interface{}, len(map1)+len(map2))
+	for k, v := range map1 {
+		merged[k] = v
+	}
 	for k, v := range map2 {
-		map1[k] = v
+		merged[k] = v
 	}
-	return map1
+	return merged
 }
 
 func getSlaveMetrics(dbVersion string) map[string][]interface{} {
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+)
+
+const (
+	// stateStoreName does not start with the integration name so that the SDK does not remove the state file
+	// along with its own metrics store when the integration runs less often than CacheTTL.
+	stateStoreName = "nri-mysql-state"
+	// stateTTL is how long the state of the integration survives without being updated.
+	stateTTL = 24 * time.Hour
+)
+
+// newStateStore returns the store persisting the state of the integration between runs, such as the run
+// count of custom queries. It falls back to an in-memory store when the state file cannot be created.
+func newStateStore(i *integration.Integration, args arguments.ArgumentList) persist.Storer {
+	path := persist.TmpPath(args.TempDir, stateStoreName+"-"+i.CreateUniqueID())
+	store, err := persist.NewFileStore(path, log.NewStdErr(args.Verbose), stateTTL)
+	if err != nil {
+		log.Warn("Error creating state store %s, state will not be kept between runs: %v", path, err)
+		return persist.NewInMemoryStore()
+	}
+	return store
+}
+
+// stateKey returns the key under which the state of a target is stored.
+func stateKey(args arguments.ArgumentList, parts ...string) string {
+	return targetName(args) + "/" + strings.Join(parts, "/")
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	dbutils "github.com/newrelic/nri-mysql/src/dbutils"
+)
+
+// targetResult holds the outcome of collecting a single MySQL instance.
+type targetResult struct {
+	args   arguments.ArgumentList
+	entity *integration.Entity
+	conn   *dbutils.ConnectionManager
+	err    error
+}
+
+// close releases the connection pool of the target, if it was opened.
+func (r targetResult) close() {
+	if r.conn != nil {
+		r.conn.Close()
+	}
+}
+
+// collectTargets runs collect for every target using at most maxConcurrent workers.
+// Results are returned in the same order as targets, and a failing target does not stop the others.
+func collectTargets(targets []arguments.ArgumentList, maxConcurrent int, collect func(arguments.ArgumentList) targetResult) []targetResult {
+	workers := min(max(maxConcurrent, 1), len(targets))
+
+	results := make([]targetResult, len(targets))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				results[idx] = collect(targets[idx])
+				if results[idx].err != nil {
+					log.Error("Error collecting target %s: %v", targetName(targets[idx]), results[idx].err)
+				}
+			}
+		}()
+	}
+
+	for idx := range targets {
+		jobs <- idx
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results
+}
+
+// targetsError returns an error only when every target failed, so that partial outages are still reported.
+func targetsError(results []targetResult) error {
+	errs := make([]error, 0, len(results))
+	for _, result := range results {
+		if result.err == nil {
+			return nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", targetName(result.args), result.err))
+	}
+	return errors.Join(errs...)
+}
+
+// targetName returns a human readable identifier of the target for logging purposes.
+func targetName(args arguments.ArgumentList) string {
+	if args.Socket != "" {
+		return args.Socket
+	}
+	return fmt.Sprint(args.Hostname, ":", args.Port)
+}
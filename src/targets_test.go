@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errTargetDown = errors.New("target down")
+
+func TestCollectTargetsBoundsConcurrency(t *testing.T) {
+	targets := make([]arguments.ArgumentList, 10)
+	for idx := range targets {
+		targets[idx] = arguments.ArgumentList{Hostname: "db", Port: 3306 + idx}
+	}
+
+	var running, maxRunning int32
+	results := collectTargets(targets, 3, func(args arguments.ArgumentList) targetResult {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			seen := atomic.LoadInt32(&maxRunning)
+			if current <= seen || atomic.CompareAndSwapInt32(&maxRunning, seen, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return targetResult{args: args}
+	})
+
+	require.Len(t, results, len(targets))
+	for idx, result := range results {
+		assert.Equal(t, targets[idx].Port, result.args.Port)
+	}
+	assert.LessOrEqual(t, maxRunning, int32(3))
+}
+
+func TestCollectTargetsContinuesAfterFailure(t *testing.T) {
+	targets := []arguments.ArgumentList{
+		{Hostname: "down", Port: 3306},
+		{Hostname: "up", Port: 3306},
+	}
+
+	results := collectTargets(targets, 0, func(args arguments.ArgumentList) targetResult {
+		if args.Hostname == "down" {
+			return targetResult{args: args, err: errTargetDown}
+		}
+		return targetResult{args: args}
+	})
+
+	require.Len(t, results, 2)
+	assert.ErrorIs(t, results[0].err, errTargetDown)
+	assert.NoError(t, results[1].err)
+	assert.NoError(t, targetsError(results))
+}
+
+func TestTargetsErrorWhenAllTargetsFail(t *testing.T) {
+	results := []targetResult{
+		{args: arguments.ArgumentList{Hostname: "db1", Port: 3306}, err: errTargetDown},
+		{args: arguments.ArgumentList{Socket: "/tmp/mysql.sock"}, err: errTargetDown},
+	}
+
+	err := targetsError(results)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, errTargetDown)
+	assert.Contains(t, err.Error(), "db1:3306")
+	assert.Contains(t, err.Error(), "/tmp/mysql.sock")
+}
+
+func TestCollectTargetsSourcesAndReplicasConcurrently(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	state := persist.NewInMemoryStore()
+
+	targets := make([]arguments.ArgumentList, 8)
+	for idx := range targets {
+		targets[idx] = arguments.ArgumentList{Hostname: "db", Port: 3306 + idx, RemoteMonitoring: true, ExtendedMetrics: true}
+	}
+
+	results := collectTargets(targets, 4, func(args arguments.ArgumentList) targetResult {
+		db := testdb{
+			inventory: map[string]interface{}{"version": "8.0.36"},
+			metrics:   map[string]interface{}{},
+			version:   map[string]interface{}{"version": "8.0.36"},
+		}
+		// Every other target is a replica
+		if args.Port%2 == 1 {
+			db.replica = map[string]interface{}{"Slave_IO_Running": "Yes", "Slave_SQL_Running": "Yes"}
+		}
+
+		e, err := infrautils.CreateNodeEntity(i, args.RemoteMonitoring, args.Hostname, args.Port)
+		if err != nil {
+			return targetResult{args: args, err: err}
+		}
+		_, err = collectTargetData(i, e, args, db, state)
+		return targetResult{args: args, entity: e, err: err}
+	})
+
+	require.Len(t, results, len(targets))
+	for _, result := range results {
+		require.NoError(t, result.err)
+		sample := result.entity.Metrics[0].Metrics
+		require.Equal(t, "MysqlSample", sample["event_type"])
+
+		isReplica := result.args.Port%2 == 1
+		assert.Equal(t, isReplica, sample["cluster.slaveRunning"] != nil, fmt.Sprint(result.args.Port))
+	}
+	assert.Contains(t, getDefaultMetrics("8.0.36"), "cluster.slaveRunning")
+}
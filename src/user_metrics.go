@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/data/attribute"
+	"github.com/newrelic/infra-integrations-sdk/v3/data/metric"
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/log"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	infrautils "github.com/newrelic/nri-mysql/src/infrautils"
+)
+
+const userSampleName = "MysqlUserSample"
+
+/*
+userMetricsQuery joins the connection counters of performance_schema.users with the statement counters of
+events_statements_summary_by_user_by_event_name. Background threads, without user, are skipped and the users
+running the most statements are reported first. Timer values are converted from picoseconds to milliseconds.
+*/
+const userMetricsQuery = `SELECT u.USER AS user_name,
+	u.CURRENT_CONNECTIONS AS current_connections, u.TOTAL_CONNECTIONS AS total_connections,
+	s.statements, s.statement_latency_ms, s.rows_examined, s.rows_sent, s.errors, s.warnings
+	FROM performance_schema.users u
+	LEFT JOIN (
+		SELECT USER, SUM(COUNT_STAR) AS statements, SUM(SUM_TIMER_WAIT) / 1000000000 AS statement_latency_ms,
+			SUM(SUM_ROWS_EXAMINED) AS rows_examined, SUM(SUM_ROWS_SENT) AS rows_sent,
+			SUM(SUM_ERRORS) AS errors, SUM(SUM_WARNINGS) AS warnings
+		FROM performance_schema.events_statements_summary_by_user_by_event_name
+		GROUP BY USER
+	) s ON s.USER = u.USER
+	WHERE u.USER IS NOT NULL
+	ORDER BY s.statements DESC
+	LIMIT ?`
+
+// accountMetricsQuery is the per account (user and client host) version of userMetricsQuery.
+const accountMetricsQuery = `SELECT a.USER AS user_name, a.HOST AS user_host,
+	a.CURRENT_CONNECTIONS AS current_connections, a.TOTAL_CONNECTIONS AS total_connections,
+	s.statements, s.statement_latency_ms, s.rows_examined, s.rows_sent, s.errors, s.warnings
+	FROM performance_schema.accounts a
+	LEFT JOIN (
+		SELECT USER, HOST, SUM(COUNT_STAR) AS statements, SUM(SUM_TIMER_WAIT) / 1000000000 AS statement_latency_ms,
+			SUM(SUM_ROWS_EXAMINED) AS rows_examined, SUM(SUM_ROWS_SENT) AS rows_sent,
+			SUM(SUM_ERRORS) AS errors, SUM(SUM_WARNINGS) AS warnings
+		FROM performance_schema.events_statements_summary_by_account_by_event_name
+		GROUP BY USER, HOST
+	) s ON s.USER = a.USER AND s.HOST = a.HOST
+	WHERE a.USER IS NOT NULL
+	ORDER BY s.statements DESC
+	LIMIT ?`
+
+// userMetrics are reported per interval: counters use PDELTA, so that the values are the difference with the
+// previous run and a counter reset, such as a server restart, skips a single interval.
+var userMetrics = map[string][]interface{}{
+	"user.currentConnections": {"current_connections", metric.GAUGE},
+	"user.connections":        {"total_connections", metric.PDELTA},
+	"user.statements":         {"statements", metric.PDELTA},
+	"user.statementLatencyMs": {"statement_latency_ms", metric.PDELTA},
+	"user.rowsExamined":       {"rows_examined", metric.PDELTA},
+	"user.rowsSent":           {"rows_sent", metric.PDELTA},
+	"user.statementErrors":    {"errors", metric.PDELTA},
+	"user.statementWarnings":  {"warnings", metric.PDELTA},
+}
+
+// populateUserMetrics reports the activity of every user, or of every account when UserMetricsByAccount is set,
+// as MysqlUserSample of the node entity.
+func populateUserMetrics(e *integration.Entity, db dataSource, args arguments.ArgumentList) {
+	query := userMetricsQuery
+	if args.UserMetricsByAccount {
+		query = accountMetricsQuery
+	}
+
+	rows, err := db.queryRows(query, "", args.UserMetricsLimit)
+	if err != nil {
+		log.Warn("Can't get user metrics (performance_schema may not be enabled): %v", err)
+		return
+	}
+
+	for _, row := range rows {
+		attributes := []attribute.Attribute{attribute.Attr("user", fmt.Sprint(row["user_name"]))}
+		if host, ok := row["user_host"]; ok {
+			attributes = append(attributes, attribute.Attr("userHost", fmt.Sprint(host)))
+		}
+
+		ms := infrautils.MetricSet(
+			e,
+			userSampleName,
+			args.Hostname,
+			args.Port,
+			args.RemoteMonitoring,
+			attributes...,
+		)
+		populatePartialMetrics(ms, row, availableMetrics(userMetrics, row), "")
+	}
+}
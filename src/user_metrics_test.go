@@ -0,0 +1,95 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/newrelic/infra-integrations-sdk/v3/integration"
+	"github.com/newrelic/infra-integrations-sdk/v3/persist"
+	arguments "github.com/newrelic/nri-mysql/src/args"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// userDB returns the rows of the user metrics query.
+type userDB struct {
+	testdb
+	rows      []map[string]interface{}
+	lastQuery string
+	args      []interface{}
+}
+
+func (d *userDB) queryRows(query string, _ string, args ...interface{}) ([]map[string]interface{}, error) {
+	d.lastQuery = query
+	d.args = args
+	return d.rows, nil
+}
+
+func TestPopulateUserMetricsReportsDeltas(t *testing.T) {
+	start := time.Now()
+	persist.SetNow(func() time.Time { return start })
+	defer persist.SetNow(time.Now)
+
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+	args := arguments.ArgumentList{Port: 3306, UserMetricsLimit: 50}
+
+	db := &userDB{rows: []map[string]interface{}{
+		{"user_name": "app", "current_connections": 4, "total_connections": 100, "statements": 1000,
+			"statement_latency_ms": 250.5, "rows_examined": 5000, "rows_sent": 900, "errors": 1, "warnings": 0},
+	}}
+	populateUserMetrics(e, db, args)
+	assert.Equal(t, userMetricsQuery, db.lastQuery)
+	assert.Equal(t, []interface{}{50}, db.args)
+
+	persist.SetNow(func() time.Time { return start.Add(30 * time.Second) })
+	db.rows = []map[string]interface{}{
+		{"user_name": "app", "current_connections": 6, "total_connections": 110, "statements": 1600,
+			"statement_latency_ms": 300.5, "rows_examined": 5500, "rows_sent": 1000, "errors": 3, "warnings": 0},
+	}
+	populateUserMetrics(e, db, args)
+
+	require.Len(t, e.Metrics, 2)
+	sample := e.Metrics[1].Metrics
+	assert.Equal(t, userSampleName, sample["event_type"])
+	assert.Equal(t, "app", sample["user"])
+	assert.Equal(t, 6., sample["user.currentConnections"])
+	assert.Equal(t, 10., sample["user.connections"])
+	assert.Equal(t, 600., sample["user.statements"])
+	assert.Equal(t, 50., sample["user.statementLatencyMs"])
+	assert.Equal(t, 2., sample["user.statementErrors"])
+}
+
+func TestPopulateUserMetricsByAccount(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	db := &userDB{rows: []map[string]interface{}{
+		{"user_name": "app", "user_host": "10.0.0.1", "current_connections": 1},
+		{"user_name": "app", "user_host": "10.0.0.2", "current_connections": 2},
+	}}
+	populateUserMetrics(e, db, arguments.ArgumentList{Port: 3306, UserMetricsByAccount: true})
+
+	assert.Equal(t, accountMetricsQuery, db.lastQuery)
+	require.Len(t, e.Metrics, 2)
+	assert.Equal(t, "10.0.0.1", e.Metrics[0].Metrics["userHost"])
+	assert.Equal(t, "10.0.0.2", e.Metrics[1].Metrics["userHost"])
+}
+
+func TestPopulateUserMetricsWithoutStatements(t *testing.T) {
+	i, err := integration.New("test", "1.0.0", integration.InMemoryStore())
+	require.NoError(t, err)
+	e := i.LocalEntity()
+
+	// Users without statements have NULL statement columns in the LEFT JOIN, which are left out of the row
+	db := &userDB{rows: []map[string]interface{}{{"user_name": "idle", "current_connections": 1, "total_connections": 3}}}
+	populateUserMetrics(e, db, arguments.ArgumentList{Port: 3306})
+
+	require.Len(t, e.Metrics, 1)
+	sample := e.Metrics[0].Metrics
+	assert.Equal(t, 1., sample["user.currentConnections"])
+	assert.NotContains(t, sample, "user.statements")
+	assert.NotContains(t, sample, "user.statementLatencyMs")
+}